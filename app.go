@@ -3,8 +3,13 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"time"
 
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/poller"
+	"DDSUViewer/internal/registers"
 	"DDSUViewer/internal/service"
 	goserial "go.bug.st/serial"
 )
@@ -48,8 +53,26 @@ func (a *App) GetElectricalData() map[string]interface{} {
 		log.Printf("Wails.GetElectricalData: 返回nil (无数据)")
 		return nil
 	}
-	
+
+	log.Printf("Wails.GetElectricalData: 返回数据 电压=%.3f, 电流=%.6f, 功率=%.3f, 频率=%.3f, 电能=%.3f",
+		data.Voltage, data.Current, data.ActivePower, data.Frequency, data.ActiveEnergy)
+
+	return a.electricalMap(data)
+}
+
+// GetDeviceElectricalData 获取总线上指定从站的电参量数据 (Wails方法)，尚无数据时返回 nil
+func (a *App) GetDeviceElectricalData(slaveID int) map[string]interface{} {
+	data := a.service.GetDeviceElectricalData(byte(slaveID))
+	if data == nil {
+		return nil
+	}
+	return a.electricalMap(data)
+}
+
+// electricalMap 将电参量数据转换为前端对象，slaveID 标明数据来源
+func (a *App) electricalMap(data *service.ElectricalData) map[string]interface{} {
 	result := map[string]interface{}{
+		"slaveID":       int(data.SlaveID),
 		"voltage":       data.Voltage,
 		"current":       data.Current,
 		"activePower":   data.ActivePower,
@@ -58,15 +81,378 @@ func (a *App) GetElectricalData() map[string]interface{} {
 		"powerFactor":   data.PowerFactor,
 		"frequency":     data.Frequency,
 		"activeEnergy":  data.ActiveEnergy,
-		"timestamp":     data.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
 	}
-	
-	log.Printf("Wails.GetElectricalData: 返回数据 电压=%.3f, 电流=%.6f, 功率=%.3f, 频率=%.3f, 电能=%.3f",
-		data.Voltage, data.Current, data.ActivePower, data.Frequency, data.ActiveEnergy)
-	
+	addSample(result, data.Sample)
+
+	// 分向电能只在当前型号提供对应寄存器时返回，前端据此决定是否显示
+	profile, err := a.service.GetActiveProfile()
+	if err != nil {
+		return result
+	}
+	for key, value := range map[string]float64{
+		registers.KeyForwardActiveEnergy:   data.ForwardActiveEnergy,
+		registers.KeyReverseActiveEnergy:   data.ReverseActiveEnergy,
+		registers.KeyForwardReactiveEnergy: data.ForwardReactiveEnergy,
+		registers.KeyReverseReactiveEnergy: data.ReverseReactiveEnergy,
+	} {
+		if profile.Has(key) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// phaseMap 将单相分量转换为前端对象
+func phaseMap(p service.PhaseData) map[string]interface{} {
+	return map[string]interface{}{
+		"voltage":       p.Voltage,
+		"current":       p.Current,
+		"activePower":   p.ActivePower,
+		"reactivePower": p.ReactivePower,
+		"apparentPower": p.ApparentPower,
+		"powerFactor":   p.PowerFactor,
+	}
+}
+
+// GetThreePhaseData 获取三相电参量数据 (Wails方法)
+// 仅在设备型号为三相电表（如 DTSU666）时有数据，否则返回 nil
+func (a *App) GetThreePhaseData() map[string]interface{} {
+	data := a.service.GetThreePhaseData()
+	if data == nil {
+		return nil
+	}
+
+	result := map[string]interface{}{
+		"slaveID":            int(data.SlaveID),
+		"phaseA":             phaseMap(data.PhaseA),
+		"phaseB":             phaseMap(data.PhaseB),
+		"phaseC":             phaseMap(data.PhaseC),
+		"voltageAB":          data.VoltageAB,
+		"voltageBC":          data.VoltageBC,
+		"voltageCA":          data.VoltageCA,
+		"totalActivePower":   data.TotalActivePower,
+		"totalReactivePower": data.TotalReactivePower,
+		"totalApparentPower": data.TotalApparentPower,
+		"totalPowerFactor":   data.TotalPowerFactor,
+		"frequency":          data.Frequency,
+		"importEnergy":       data.ImportEnergy,
+		"exportEnergy":       data.ExportEnergy,
+	}
+	addSample(result, data.Sample)
+	return result
+}
+
+// addSample 写入采集信息：timestamp 为采集时间，stale 为 true 时界面应将非 good 的数值置灰
+// quality 为 数据点键 -> good/stale/invalid/comm-failed，latencyMs 为本次读取往返耗时
+func addSample(result map[string]interface{}, sample service.Sample) {
+	quality := make(map[string]string, len(sample.Quality))
+	for key, q := range sample.Quality {
+		quality[key] = string(q)
+	}
+	result["timestamp"] = sample.Timestamp.Format("2006-01-02T15:04:05Z07:00")
+	result["latencyMs"] = float64(sample.Latency) / float64(time.Millisecond)
+	result["quality"] = quality
+	result["stale"] = sample.Stale
+	result["error"] = sample.Error
+}
+
+// GetDeviceStatus 获取设备状态 (Wails方法)
+// errorMessage 包含最近一次通信错误，如 Modbus 异常 "非法数据地址 (illegal data address)"
+func (a *App) GetDeviceStatus() map[string]interface{} {
+	status := a.service.GetDeviceStatus()
+	return map[string]interface{}{
+		"connected":    status.Connected(),
+		"state":        string(status.State),
+		"protocol":     status.Protocol,
+		"lastUpdate":   status.LastUpdate.Format("2006-01-02T15:04:05Z07:00"),
+		"errorMessage": status.ErrorMessage,
+	}
+}
+
+// GetBusDevices 获取总线上各从站的采集状态 (Wails方法)，主设备在前
+// 离线从站按退避间隔探测，nextPoll 为下一次探测时间
+func (a *App) GetBusDevices() []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, d := range a.service.GetBusDevices() {
+		device := map[string]interface{}{
+			"slaveID":      int(d.SlaveID),
+			"primary":      d.Primary,
+			"online":       d.Online,
+			"failures":     d.Failures,
+			"lastSeen":     "",
+			"nextPoll":     "",
+			"errorMessage": d.ErrorMessage,
+		}
+		if !d.LastSeen.IsZero() {
+			device["lastSeen"] = d.LastSeen.Format("2006-01-02T15:04:05Z07:00")
+		}
+		if !d.NextPoll.IsZero() {
+			device["nextPoll"] = d.NextPoll.Format("2006-01-02T15:04:05Z07:00")
+		}
+		result = append(result, device)
+	}
+	return result
+}
+
+// SetBusSlaveIDs 设置同一总线上一并轮询的其他从站地址 (Wails方法)，采集进行中时立即生效
+func (a *App) SetBusSlaveIDs(slaveIDs []int) bool {
+	if err := a.service.SetBusSlaveIDs(slaveIDs); err != nil {
+		log.Printf("设置总线从站失败: %v", err)
+		return false
+	}
+	return true
+}
+
+// GetPollSchedule 获取各分组的轮询间隔 (Wails方法)，单位毫秒，0 表示仅按需读取
+func (a *App) GetPollSchedule() map[string]int {
+	return scheduleMap(a.service.GetPollSchedule())
+}
+
+// SetPollSchedule 设置各分组的轮询间隔 (Wails方法)，单位毫秒，如 {"electrical": 250, "energy": 60000}
+// 采集进行中时立即生效，无需重新连接；总线跟不上时跳过节拍而不是排队补读
+func (a *App) SetPollSchedule(intervals map[string]int) bool {
+	schedule := make(poller.Schedule, len(intervals))
+	for group, ms := range intervals {
+		schedule[group] = time.Duration(ms) * time.Millisecond
+	}
+	if err := a.service.SetPollSchedule(schedule); err != nil {
+		log.Printf("设置轮询间隔失败: %v", err)
+		return false
+	}
+	return true
+}
+
+// scheduleMap 将轮询计划转换为 分组 -> 毫秒
+func scheduleMap(schedule poller.Schedule) map[string]int {
+	result := make(map[string]int, len(schedule))
+	for group, d := range schedule {
+		result[group] = int(d / time.Millisecond)
+	}
+	return result
+}
+
+// GetConnectionState 获取连接状态机的当前状态 (Wails方法)
+// state 为 disconnected/connecting/online/degraded/offline/error/reconnecting，连续失败达到 offlineAfter 次进入 offline（通道标红）；
+// USB 串口适配器拔出后进入 reconnecting 并按退避间隔自动重连，reconnectAttempt 为已尝试次数
+func (a *App) GetConnectionState() map[string]interface{} {
+	status := a.service.GetDeviceStatus()
+	thresholds := a.service.GetStateThresholds()
+	return map[string]interface{}{
+		"state":            string(status.State),
+		"since":            status.Since.Format("2006-01-02T15:04:05Z07:00"),
+		"failures":         status.Failures,
+		"reconnectAttempt": status.ReconnectAttempt,
+		"degradedAfter":    thresholds.DegradedAfter,
+		"offlineAfter":     thresholds.OfflineAfter,
+		"errorMessage":     status.ErrorMessage,
+	}
+}
+
+// SetStateThresholds 设置连续失败多少次进入 degraded / offline (Wails方法)，立即生效
+func (a *App) SetStateThresholds(degradedAfter int, offlineAfter int) bool {
+	t := service.StateThresholds{DegradedAfter: degradedAfter, OfflineAfter: offlineAfter}
+	if err := a.service.SetStateThresholds(t); err != nil {
+		log.Printf("设置状态阈值失败: %v", err)
+		return false
+	}
+	return true
+}
+
+// GetDeviceProfiles 获取可用的设备型号 (Wails方法)
+// 内置 DDSU666，另可在 data/profiles/*.json 中添加自定义设备描述
+func (a *App) GetDeviceProfiles() []string {
+	return a.service.GetProfiles()
+}
+
+// GetDeviceProfile 获取当前设备型号 (Wails方法)
+func (a *App) GetDeviceProfile() string {
+	profile, err := a.service.GetActiveProfile()
+	if err != nil {
+		log.Printf("获取设备型号失败: %v", err)
+		return ""
+	}
+	return profile.Name
+}
+
+// SetDeviceProfile 切换设备型号 (Wails方法)
+func (a *App) SetDeviceProfile(name string) bool {
+	if err := a.service.SetProfile(name); err != nil {
+		log.Printf("切换设备型号失败: %v", err)
+		return false
+	}
+	return true
+}
+
+// GetDataPoints 获取当前设备型号的数据点定义 (Wails方法)
+func (a *App) GetDataPoints() []map[string]interface{} {
+	profile, err := a.service.GetActiveProfile()
+	if err != nil {
+		log.Printf("获取数据点定义失败: %v", err)
+		return []map[string]interface{}{}
+	}
+	points := profile.DataPoints()
+	result := make([]map[string]interface{}, 0, len(points))
+	for _, dp := range points {
+		result = append(result, map[string]interface{}{
+			"key":     dp.Key,
+			"name":    dp.Name,
+			"address": dp.Address,
+			"unit":    dp.Unit,
+		})
+	}
 	return result
 }
 
+// CalibrateByteOrder 端序校准：读取电参量并列出四种字节序的解析结果 (Wails方法)
+// 按可信度（电压 100–260V、频率 45–65Hz 等）从高到低排序，供用户与电表屏显比对
+func (a *App) CalibrateByteOrder() []map[string]interface{} {
+	candidates, err := a.service.CalibrateByteOrder()
+	if err != nil {
+		log.Printf("端序校准失败: %v", err)
+		return []map[string]interface{}{}
+	}
+	result := make([]map[string]interface{}, 0, len(candidates))
+	for _, c := range candidates {
+		result = append(result, map[string]interface{}{
+			"byteOrder": c.ByteOrder,
+			"score":     c.Score,
+			"values":    c.Values,
+		})
+	}
+	return result
+}
+
+// ConfirmByteOrder 确认端序校准结果并持久化 (Wails方法)
+// byteOrder 为 "ABCD"、"CDAB"、"BADC" 或 "DCBA"，空字符串恢复设备描述默认值
+func (a *App) ConfirmByteOrder(byteOrder string) bool {
+	if err := a.service.SetByteOrder(byteOrder); err != nil {
+		log.Printf("保存端序失败: %v", err)
+		return false
+	}
+	return true
+}
+
+// GetMeterSettings 读取电表参数 (Wails方法)
+// 返回 ucode、revision、protocol（1=DL/T645-2007，2=Modbus-RTU）、address、baudRate；读取失败返回 nil
+func (a *App) GetMeterSettings() map[string]interface{} {
+	settings, err := a.service.GetMeterSettings()
+	if err != nil {
+		log.Printf("读取电表参数失败: %v", err)
+		return nil
+	}
+	return map[string]interface{}{
+		"ucode":    settings.UCode,
+		"revision": settings.Revision,
+		"protocol": settings.Protocol,
+		"address":  settings.Address,
+		"baudRate": settings.BaudRate,
+	}
+}
+
+// UpdateMeterSettings 修改电表通讯地址 (1-247) 与波特率 (1200/2400/4800/9600) (Wails方法)
+// 修改成功后自动以新参数重连
+func (a *App) UpdateMeterSettings(address int, baudRate int) bool {
+	if address < 1 || address > 247 {
+		log.Printf("修改电表参数失败: 通讯地址必须在 1-247 之间: %d", address)
+		return false
+	}
+	if err := a.service.UpdateMeterSettings(byte(address), baudRate); err != nil {
+		log.Printf("修改电表参数失败: %v", err)
+		return false
+	}
+	return true
+}
+
+// ClearMeterEnergy 清零电表电能 (Wails方法)，前端须先弹窗确认
+func (a *App) ClearMeterEnergy() bool {
+	if err := a.service.ClearEnergy(); err != nil {
+		log.Printf("清零电能失败: %v", err)
+		return false
+	}
+	return true
+}
+
+// ReadRawRegisters 寄存器浏览：读取任意地址区间并给出多种解读 (Wails方法)
+// function 为 3（保持寄存器）或 4（输入寄存器），quantity 为 1-125
+// 返回 words、ascii 与逐寄存器的 uint16/int16/float32（四种字节序）；失败时返回 error 字段说明原因
+func (a *App) ReadRawRegisters(function int, start int, quantity int) map[string]interface{} {
+	if function < 0 || function > 0xFF || start < 0 || start > 0xFFFF || quantity < 0 || quantity > 0xFFFF {
+		return map[string]interface{}{"error": fmt.Sprintf("参数非法: function=%d start=%d quantity=%d", function, start, quantity)}
+	}
+	reading, err := a.service.ReadRawRegisters(byte(function), uint16(start), uint16(quantity))
+	if err != nil {
+		log.Printf("读取寄存器失败: %v", err)
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	regs := make([]map[string]interface{}, 0, len(reading.Registers))
+	for _, r := range reading.Registers {
+		regs = append(regs, map[string]interface{}{
+			"address": r.Address,
+			"hex":     fmt.Sprintf("%04X", r.Uint16),
+			"uint16":  r.Uint16,
+			"int16":   r.Int16,
+			"float32": r.Float32,
+		})
+	}
+	return map[string]interface{}{
+		"function":  reading.Function,
+		"start":     reading.Start,
+		"words":     reading.Words,
+		"ascii":     reading.ASCII,
+		"registers": regs,
+	}
+}
+
+// SendConsoleFrame 调试控制台：发送十六进制帧如 "0C 03 20 00 00 02" (Wails方法)
+// appendCRC 为 true 时自动追加 CRC；返回请求/应答原文、耗时 (ms)、CRC 校验结果与逐字段解析，失败时返回 error 字段
+// crc 为 ok/bad/n/a：Modbus TCP 应答不含 CRC，记为 n/a；该链路下发送的帧须带正确的 CRC
+func (a *App) SendConsoleFrame(frame string, appendCRC bool) map[string]interface{} {
+	tx, err := a.service.SendRawFrame(frame, appendCRC)
+	if err != nil {
+		log.Printf("发送调试帧失败: %v", err)
+		return map[string]interface{}{"error": err.Error()}
+	}
+	return consoleMap(tx)
+}
+
+// GetConsoleHistory 获取本次会话的调试控制台收发记录 (Wails方法)
+func (a *App) GetConsoleHistory() []map[string]interface{} {
+	history := a.service.GetConsoleHistory()
+	result := make([]map[string]interface{}, 0, len(history))
+	for i := range history {
+		result = append(result, consoleMap(&history[i]))
+	}
+	return result
+}
+
+// ClearConsoleHistory 清空调试控制台收发记录 (Wails方法)
+func (a *App) ClearConsoleHistory() bool {
+	a.service.ClearConsoleHistory()
+	return true
+}
+
+// consoleMap 将调试控制台记录转换为前端格式
+func consoleMap(tx *service.ConsoleTransaction) map[string]interface{} {
+	fields := func(ff []modbus.FrameField) []map[string]interface{} {
+		out := make([]map[string]interface{}, 0, len(ff))
+		for _, f := range ff {
+			out = append(out, map[string]interface{}{"name": f.Name, "hex": f.Hex, "value": f.Value})
+		}
+		return out
+	}
+	return map[string]interface{}{
+		"time":           tx.Time.Format("2006-01-02T15:04:05.000Z07:00"),
+		"request":        modbus.FormatHex(tx.Request),
+		"response":       modbus.FormatHex(tx.Response),
+		"durationMs":     float64(tx.Duration.Microseconds()) / 1000,
+		"crc":            string(tx.CRC),
+		"error":          tx.Error,
+		"requestFields":  fields(tx.RequestFields),
+		"responseFields": fields(tx.ResponseFields),
+	}
+}
+
 // StartPolling 启动数据采集 (Wails方法)
 func (a *App) StartPolling() bool {
 	err := a.service.StartPolling()
@@ -108,16 +494,28 @@ func parseStopBitsParity(stopBits int, parity string) (goserial.StopBits, goseri
 }
 
 // UpdateSerialConfig 更新串口配置 (Wails方法)
-func (a *App) UpdateSerialConfig(port string, baudRate int, dataBits int, stopBits int, parity string, slaveID int) bool {
+// transportType 为 "serial"、"tcp"、"rtu-tcp" 或 "sim"；tcp/rtu-tcp 使用 host/tcpPort，
+// tcp 模式下 SlaveID 作为单元ID，rtu-tcp 模式下原样透传 RTU 帧，sim 连接内置仿真电表
+func (a *App) UpdateSerialConfig(port string, baudRate int, dataBits int, stopBits int, parity string, slaveID int, transportType string, host string, tcpPort int) bool {
 	sb, p := parseStopBitsParity(stopBits, parity)
+	current := a.service.GetSerialConfig()
 
 	config := &service.SerialConfig{
-		Port:     port,
-		BaudRate: baudRate,
-		DataBits: dataBits,
-		StopBits: sb,
-		Parity:   p,
-		SlaveID:  slaveID,
+		Port:      port,
+		BaudRate:  baudRate,
+		DataBits:  dataBits,
+		StopBits:  sb,
+		Parity:    p,
+		SlaveID:   slaveID,
+		Transport: transportType,
+		Host:      host,
+		TCPPort:   tcpPort,
+		SlaveIDs:  current.SlaveIDs,  // 总线从站通过 SetBusSlaveIDs 单独设置
+		Profile:   current.Profile,   // 设备型号通过 SetDeviceProfile 单独设置
+		ByteOrder: current.ByteOrder, // 端序通过 ConfirmByteOrder 单独设置
+		Schedule:  current.Schedule,  // 轮询间隔通过 SetPollSchedule 单独设置
+
+		Thresholds: current.Thresholds, // 状态阈值通过 SetStateThresholds 单独设置
 	}
 
 	err := a.service.UpdateSerialConfig(config)
@@ -129,16 +527,26 @@ func (a *App) UpdateSerialConfig(port string, baudRate int, dataBits int, stopBi
 }
 
 // SaveSavedSerialConfig 将当前配置以快照形式持久化到后端（Wails方法）
-func (a *App) SaveSavedSerialConfig(port string, baudRate int, dataBits int, stopBits int, parity string, slaveID int) bool {
+func (a *App) SaveSavedSerialConfig(port string, baudRate int, dataBits int, stopBits int, parity string, slaveID int, transportType string, host string, tcpPort int) bool {
 	sb, p := parseStopBitsParity(stopBits, parity)
+	current := a.service.GetSerialConfig()
 
 	cfg := &service.SerialConfig{
-		Port:     port,
-		BaudRate: baudRate,
-		DataBits: dataBits,
-		StopBits: sb,
-		Parity:   p,
-		SlaveID:  slaveID,
+		Port:      port,
+		BaudRate:  baudRate,
+		DataBits:  dataBits,
+		StopBits:  sb,
+		Parity:    p,
+		SlaveID:   slaveID,
+		Transport: transportType,
+		Host:      host,
+		TCPPort:   tcpPort,
+		SlaveIDs:  current.SlaveIDs,  // 总线从站通过 SetBusSlaveIDs 单独设置
+		Profile:   current.Profile,   // 设备型号通过 SetDeviceProfile 单独设置
+		ByteOrder: current.ByteOrder, // 端序通过 ConfirmByteOrder 单独设置
+		Schedule:  current.Schedule,  // 轮询间隔通过 SetPollSchedule 单独设置
+
+		Thresholds: current.Thresholds, // 状态阈值通过 SetStateThresholds 单独设置
 	}
 
 	if err := a.service.SaveSavedSerialConfig(cfg); err != nil {
@@ -162,12 +570,25 @@ func (a *App) LoadSavedSerialConfig() string {
 
 	// 将配置序列化为简单 JSON，以便前端直接使用
 	out := map[string]interface{}{
-		"port":     cfg.Port,
-		"baudRate": cfg.BaudRate,
-		"dataBits": cfg.DataBits,
-		"stopBits": int(cfg.StopBits),
-		"parity":   int(cfg.Parity),
-		"slaveID":  cfg.SlaveID,
+		"port":      cfg.Port,
+		"baudRate":  cfg.BaudRate,
+		"dataBits":  cfg.DataBits,
+		"stopBits":  int(cfg.StopBits),
+		"parity":    int(cfg.Parity),
+		"slaveID":   cfg.SlaveID,
+		"slaveIDs":  cfg.SlaveIDs,
+		"transport": cfg.Transport,
+		"host":      cfg.Host,
+		"tcpPort":   cfg.TCPPort,
+		"profile":   cfg.Profile,
+		"byteOrder": cfg.ByteOrder,
+	}
+	if cfg.Schedule != nil {
+		out["schedule"] = scheduleMap(cfg.Schedule)
+	}
+	if cfg.Thresholds != (service.StateThresholds{}) {
+		out["degradedAfter"] = cfg.Thresholds.DegradedAfter
+		out["offlineAfter"] = cfg.Thresholds.OfflineAfter
 	}
 	b, err := json.Marshal(out)
 	if err != nil {
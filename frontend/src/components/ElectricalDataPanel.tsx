@@ -2,30 +2,43 @@ import { Box, Card, SimpleGrid, Text, Badge, Flex } from '@chakra-ui/react';
 import { useAppStore } from '../hooks/usePolling';
 import { mdColors, dataColors } from '../theme/colors';
 
-const DataCard = ({ title, value, unit, color = 'blue' }: {
+// 非 good 质量的说明文字
+const qualityLabels: Record<string, string> = {
+  stale: '沿用上次数值',
+  invalid: '数值无效',
+  'comm-failed': '读取失败',
+};
+
+const DataCard = ({ title, value, unit, color = 'blue', quality }: {
   title: string;
   value?: number;
   unit: string;
   color?: string;
-}) => (
-  <Card.Root 
-    bg={mdColors.cardBackground} 
-    shadow="md" 
-    borderRadius="xl" 
-    border="1px" 
-    borderColor={mdColors.outlineVariant}
-    _hover={{ shadow: "lg", transform: "translateY(-2px)" }}
-    transition="all 0.2s"
-  >
-    <Card.Body p={6}>
-      <Text fontSize="sm" color={mdColors.onSurfaceVariant} mb={2} fontWeight="medium">{title}</Text>
-      <Text fontSize="3xl" fontWeight="bold" color={value !== undefined ? dataColors[color as keyof typeof dataColors] : mdColors.outline} mb={1}>
-        {value !== undefined ? value.toFixed(3) : '未知'}
-      </Text>
-      <Text fontSize="sm" color={mdColors.onSurfaceVariant} fontWeight="medium">{unit}</Text>
-    </Card.Body>
-  </Card.Root>
-);
+  quality?: string;
+}) => {
+  const degraded = quality !== undefined && quality !== 'good';
+  return (
+    <Card.Root 
+      bg={mdColors.cardBackground} 
+      shadow="md" 
+      borderRadius="xl" 
+      border="1px" 
+      borderColor={mdColors.outlineVariant}
+      _hover={{ shadow: "lg", transform: "translateY(-2px)" }}
+      transition="all 0.2s"
+    >
+      <Card.Body p={6}>
+        <Text fontSize="sm" color={mdColors.onSurfaceVariant} mb={2} fontWeight="medium">{title}</Text>
+        <Text fontSize="3xl" fontWeight="bold" color={value !== undefined && !degraded ? dataColors[color as keyof typeof dataColors] : mdColors.outline} mb={1}>
+          {value !== undefined ? value.toFixed(3) : '未知'}
+        </Text>
+        <Text fontSize="sm" color={mdColors.onSurfaceVariant} fontWeight="medium">
+          {unit}{degraded ? ` · ${qualityLabels[quality!] ?? quality}` : ''}
+        </Text>
+      </Card.Body>
+    </Card.Root>
+  );
+};
 
 export const ElectricalDataPanel = () => {
   const { data: electricalData } = useAppStore();
@@ -35,7 +48,7 @@ export const ElectricalDataPanel = () => {
       <Flex mb={6} align="center" justify="space-between">
         <Text fontSize="xl" fontWeight="bold" color={mdColors.onSurface}>实时电参量数据</Text>
         <Badge bg={mdColors.secondaryContainer} color={mdColors.onSecondaryContainer} px={3} py={1} borderRadius="full">
-          实时更新
+          {electricalData?.stale ? '部分数据未更新' : '实时更新'}
         </Badge>
       </Flex>
       
@@ -43,56 +56,102 @@ export const ElectricalDataPanel = () => {
         <DataCard 
           title="电压" 
           value={electricalData?.voltage} 
+          quality={electricalData?.quality?.voltage} 
           unit="V" 
           color="blue" 
         />
         <DataCard 
           title="电流" 
           value={electricalData?.current} 
+          quality={electricalData?.quality?.current} 
           unit="A" 
           color="orange" 
         />
         <DataCard 
           title="有功功率" 
           value={electricalData?.activePower} 
+          quality={electricalData?.quality?.activePower} 
           unit="W" 
           color="green" 
         />
         <DataCard 
           title="无功功率" 
           value={electricalData?.reactivePower} 
+          quality={electricalData?.quality?.reactivePower} 
           unit="Var" 
           color="purple" 
         />
         <DataCard 
           title="视在功率" 
           value={electricalData?.apparentPower} 
+          quality={electricalData?.quality?.apparentPower} 
           unit="VA" 
           color="teal" 
         />
         <DataCard 
           title="功率因数" 
           value={electricalData?.powerFactor} 
+          quality={electricalData?.quality?.powerFactor} 
           unit="" 
           color="cyan" 
         />
         <DataCard 
           title="频率" 
           value={electricalData?.frequency} 
+          quality={electricalData?.quality?.frequency} 
           unit="Hz" 
           color="pink" 
         />
         <DataCard 
           title="有功总电能" 
           value={electricalData?.activeEnergy} 
+          quality={electricalData?.quality?.activeEnergy} 
           unit="kWh" 
           color="red" 
         />
+        {electricalData?.forwardActiveEnergy !== undefined && (
+          <DataCard 
+            title="正向有功电能" 
+            value={electricalData.forwardActiveEnergy} 
+            quality={electricalData?.quality?.forwardActiveEnergy} 
+            unit="kWh" 
+            color="red" 
+          />
+        )}
+        {electricalData?.reverseActiveEnergy !== undefined && (
+          <DataCard 
+            title="反向有功电能" 
+            value={electricalData.reverseActiveEnergy} 
+            quality={electricalData?.quality?.reverseActiveEnergy} 
+            unit="kWh" 
+            color="green" 
+          />
+        )}
+        {electricalData?.forwardReactiveEnergy !== undefined && (
+          <DataCard 
+            title="正向无功电能" 
+            value={electricalData.forwardReactiveEnergy} 
+            quality={electricalData?.quality?.forwardReactiveEnergy} 
+            unit="kvarh" 
+            color="purple" 
+          />
+        )}
+        {electricalData?.reverseReactiveEnergy !== undefined && (
+          <DataCard 
+            title="反向无功电能" 
+            value={electricalData.reverseReactiveEnergy} 
+            quality={electricalData?.quality?.reverseReactiveEnergy} 
+            unit="kvarh" 
+            color="teal" 
+          />
+        )}
       </SimpleGrid>
       
       <Box mt={6} pt={4} borderTop="1px" borderColor={mdColors.outlineVariant}>
         <Text fontSize="sm" color={mdColors.onSurfaceVariant} textAlign="center">
-          最后更新: {electricalData?.timestamp ? new Date(electricalData.timestamp).toLocaleString() : '无'}
+          最后采集: {electricalData?.timestamp ? new Date(electricalData.timestamp).toLocaleString() : '无'}
+          {electricalData?.latencyMs !== undefined && ` · 往返 ${electricalData.latencyMs} ms`}
+          {electricalData?.error && ` · ${electricalData.error}`}
         </Text>
       </Box>
     </Box>
@@ -8,7 +8,7 @@ import {
   HStack,
   Button,
 } from '@chakra-ui/react';
-import { GetAvailablePorts, StartPolling, StopPolling, UpdateSerialConfig } from '../../wailsjs/go/main/App';
+import { GetAvailablePorts, GetDeviceProfiles, SetDeviceProfile, StartPolling, StopPolling, UpdateSerialConfig } from '../../wailsjs/go/main/App';
 import { useAppStore, updateStatus } from '../hooks/usePolling';
 import { mdColors } from '../theme/colors';
 
@@ -19,6 +19,10 @@ interface SerialConfig {
   stopBits: number;
   parity: string;
   slaveID: number;
+  transport?: string;
+  host?: string;
+  tcpPort?: number;
+  profile?: string;
 }
 
 interface CustomSelectProps {
@@ -238,6 +242,7 @@ export const SerialConfigPanel = () => {
   const [slaveID, setSlaveID] = useState('');
   const [originalSlaveID, setOriginalSlaveID] = useState('');
   const [ports, setPorts] = useState<string[]>([]);
+  const [profiles, setProfiles] = useState<string[]>(['DDSU666']);
   const [toast, setToast] = useState<{ message: string; type: 'success' | 'error' | 'warning' } | null>(null);
   const [config, setConfig] = useState<SerialConfig>({
     port: '',
@@ -259,6 +264,10 @@ export const SerialConfigPanel = () => {
   // 辅助：将解析后的部分配置应用到组件状态（包含从站地址的十六进制展示）
   const applyParsedToState = (parsed: Partial<SerialConfig>) => {
     setConfig(cfg => ({ ...cfg, ...parsed }));
+    if (parsed.profile) {
+      // 设备型号属于已保存配置的一部分，恢复时同步到后端
+      SetDeviceProfile(parsed.profile).catch(console.error);
+    }
     if (parsed.slaveID !== undefined && parsed.slaveID !== null && Number(parsed.slaveID) > 0) {
       const hex = Number(parsed.slaveID).toString(16).toUpperCase().padStart(2, '0');
       setSlaveID(hex);
@@ -327,6 +336,12 @@ export const SerialConfigPanel = () => {
       }
     };
     loadPorts();
+
+    GetDeviceProfiles()
+      .then(list => {
+        if (list && list.length > 0) setProfiles(list);
+      })
+      .catch(console.error);
   }, []);
 
   const persistConfig = (c: SerialConfig) => {
@@ -350,7 +365,10 @@ export const SerialConfigPanel = () => {
         newConfig.dataBits,
         newConfig.stopBits,
         newConfig.parity,
-        newConfig.slaveID
+        newConfig.slaveID,
+        newConfig.transport || 'serial',
+        newConfig.host || '',
+        Number(newConfig.tcpPort || 502)
       );
       
       if (result) {
@@ -363,6 +381,22 @@ export const SerialConfigPanel = () => {
     }
   };
 
+  const handleProfileChange = async (value: string) => {
+    try {
+      const result = await SetDeviceProfile(value);
+      if (result) {
+        const newConfig = { ...config, profile: value };
+        setConfig(newConfig);
+        persistConfig(newConfig);
+        showToast(`设备型号已切换为 ${value}`, 'success');
+      } else {
+        showToast('切换设备型号失败', 'error');
+      }
+    } catch (error: any) {
+      showToast(error.message || '切换设备型号失败', 'error');
+    }
+  };
+
   const handleSlaveIDChange = (value: string) => {
     setSlaveID(value.toUpperCase());
     // 不在输入时显示警告，只在尝试连接时检查
@@ -434,6 +468,16 @@ export const SerialConfigPanel = () => {
         </Card.Header>
         <Card.Body pt={2}>
           <VStack gap={4} align="stretch">
+            {/* 设备型号 */}
+            <Box>
+              <Text fontSize="sm" mb={2} fontWeight="medium" color="gray.700">设备型号</Text>
+              <CustomSelect
+                value={config.profile || 'DDSU666'}
+                options={profiles.map(name => ({ label: name, value: name }))}
+                onChange={handleProfileChange}
+              />
+            </Box>
+
             {/* COM 端口 */}
             <Box>
               <Text fontSize="sm" mb={2} fontWeight="medium" color="gray.700">COM 端口</Text>
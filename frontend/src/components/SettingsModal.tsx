@@ -176,14 +176,17 @@ export const SettingsModal: React.FC<{
             return;
           }
 
-          // 按照 Wails 生成的绑定签名，传入 6 个参数
+          // 按照 Wails 生成的绑定签名，传入 9 个参数
           await SaveSavedSerialConfig(
             parsed.port || '',
             Number(parsed.baudRate || 9600),
             Number(parsed.dataBits || 8),
             Number(parsed.stopBits || 1),
             parsed.parity || 'None',
-            Number(parsed.slaveID || 0)
+            Number(parsed.slaveID || 0),
+            parsed.transport || 'serial',
+            parsed.host || '',
+            Number(parsed.tcpPort || 502)
           );
           // 后端成功或抛出前，我们都在 localStorage 中写入 JSON 字符串作为回退
           localStorage.setItem(SAVED_SERIAL_KEY, JSON.stringify(parsed));
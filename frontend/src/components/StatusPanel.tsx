@@ -2,8 +2,21 @@ import { Box, Card, Text, Badge, VStack, HStack } from '@chakra-ui/react';
 import { useAppStore } from '../hooks/usePolling';
 import { mdColors } from '../theme/colors';
 
+// 连接状态显示文字；offline 与 error 标红
+const stateLabels: Record<string, string> = {
+  disconnected: '未连接',
+  connecting: '连接中',
+  online: '在线',
+  degraded: '通信不稳定',
+  offline: '离线',
+  error: '错误',
+  reconnecting: '重连中',
+};
+
 export const StatusPanel = () => {
   const { status } = useAppStore();
+  const state = status.connected ? status.state ?? 'connecting' : 'disconnected';
+  const healthy = state === 'online' || state === 'degraded' || state === 'connecting';
 
   return (
     <Card.Root bg={mdColors.surface} shadow="md" borderRadius="xl" border="1px" borderColor={mdColors.outlineVariant}>
@@ -16,12 +29,14 @@ export const StatusPanel = () => {
           <HStack justify="space-between">
             <Text fontSize="sm">连接状态</Text>
             <Badge 
-              bg={status.connected ? mdColors.secondaryContainer : mdColors.errorContainer}
-              color={status.connected ? mdColors.onSecondaryContainer : mdColors.error}
+              bg={healthy ? mdColors.secondaryContainer : mdColors.errorContainer}
+              color={healthy ? mdColors.onSecondaryContainer : mdColors.error}
               px={2}
               textAlign="center"
             >
-              {status.connected ? '已连接' : '未连接'}
+              {stateLabels[state] ?? state}
+              {state === 'degraded' || state === 'offline' ? ` (连续失败 ${status.failures ?? 0} 次)` : ''}
+              {state === 'reconnecting' && status.reconnectAttempt ? ` (第 ${status.reconnectAttempt} 次)` : ''}
             </Badge>
           </HStack>
 
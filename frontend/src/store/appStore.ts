@@ -1,5 +1,9 @@
 interface DeviceStatus {
-  connected: boolean;
+  connected: boolean; // 采集是否已启动
+  // 后端连接状态机：disconnected / connecting / online / degraded / offline / error / reconnecting
+  state?: string;
+  failures?: number;
+  reconnectAttempt?: number; // 适配器拔出后的自动重连次数
   protocol: string;
   lastUpdate: string;
   errorMessage?: string;
@@ -14,7 +18,17 @@ interface ElectricalData {
   powerFactor: number;
   frequency: number;
   activeEnergy: number;
-  timestamp: string;
+  // 分向电能，仅在电表提供对应寄存器时存在
+  forwardActiveEnergy?: number;
+  reverseActiveEnergy?: number;
+  forwardReactiveEnergy?: number;
+  reverseReactiveEnergy?: number;
+  timestamp: string; // 采集时间
+  // 数据点键 -> good / stale / invalid / comm-failed，非 good 的数值应置灰
+  quality?: Record<string, string>;
+  stale?: boolean;
+  latencyMs?: number;
+  error?: string;
 }
 
 class AppStore {
@@ -60,7 +74,20 @@ class AppStore {
         // 获取真实数据
         try {
           // @ts-ignore
-          const { GetElectricalData } = window.go?.main?.App || {};
+          const { GetElectricalData, GetConnectionState } = window.go?.main?.App || {};
+          if (GetConnectionState) {
+            const conn = await GetConnectionState();
+            if (conn && (conn.state !== this.status.state || conn.failures !== this.status.failures || conn.reconnectAttempt !== this.status.reconnectAttempt)) {
+              this.status = {
+                ...this.status,
+                state: conn.state,
+                failures: conn.failures,
+                reconnectAttempt: conn.reconnectAttempt,
+                errorMessage: conn.errorMessage || this.status.errorMessage,
+              };
+              this.notifyListeners();
+            }
+          }
           if (GetElectricalData) {
             const realData = await GetElectricalData();
             if (realData && this.isValidElectricalData(realData)) {
@@ -73,7 +100,15 @@ class AppStore {
                 powerFactor: this.formatNumber(realData.powerFactor, 3),
                 frequency: this.formatNumber(realData.frequency, 2),
                 activeEnergy: this.formatNumber(realData.activeEnergy, 3),
+                forwardActiveEnergy: this.optionalNumber(realData.forwardActiveEnergy, 3),
+                reverseActiveEnergy: this.optionalNumber(realData.reverseActiveEnergy, 3),
+                forwardReactiveEnergy: this.optionalNumber(realData.forwardReactiveEnergy, 3),
+                reverseReactiveEnergy: this.optionalNumber(realData.reverseReactiveEnergy, 3),
                 timestamp: realData.timestamp || new Date().toISOString(),
+                quality: realData.quality,
+                stale: realData.stale,
+                latencyMs: this.optionalNumber(realData.latencyMs, 1),
+                error: realData.error || undefined,
               };
               this.notifyListeners();
             } else {
@@ -128,6 +163,14 @@ class AppStore {
     }
     return Number(value.toFixed(decimals));
   }
+
+  // 可选字段：后端未返回时保持 undefined，界面据此隐藏
+  private optionalNumber(value: number | undefined, decimals: number): number | undefined {
+    if (value === undefined || value === null) {
+      return undefined;
+    }
+    return this.formatNumber(value, decimals);
+  }
 }
 
 export const appStore = new AppStore();
\ No newline at end of file
@@ -1,18 +1,62 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CalibrateByteOrder():Promise<Array<Record<string, any>>>;
+
+export function ClearConsoleHistory():Promise<boolean>;
+
+export function ClearMeterEnergy():Promise<boolean>;
+
 export function ClearSavedSerialConfig():Promise<boolean>;
 
+export function ConfirmByteOrder(arg1:string):Promise<boolean>;
+
 export function GetAvailablePorts():Promise<Array<string>>;
 
+export function GetBusDevices():Promise<Array<Record<string, any>>>;
+
+export function GetConnectionState():Promise<Record<string, any>>;
+
+export function GetConsoleHistory():Promise<Array<Record<string, any>>>;
+
+export function GetDataPoints():Promise<Array<Record<string, any>>>;
+
+export function GetDeviceElectricalData(arg1:number):Promise<Record<string, any>>;
+
+export function GetDeviceProfile():Promise<string>;
+
+export function GetDeviceProfiles():Promise<Array<string>>;
+
+export function GetDeviceStatus():Promise<Record<string, any>>;
+
 export function GetElectricalData():Promise<Record<string, any>>;
 
+export function GetMeterSettings():Promise<Record<string, any>>;
+
+export function GetPollSchedule():Promise<Record<string, number>>;
+
+export function GetThreePhaseData():Promise<Record<string, any>>;
+
 export function LoadSavedSerialConfig():Promise<string>;
 
-export function SaveSavedSerialConfig(arg1:string,arg2:number,arg3:number,arg4:number,arg5:string,arg6:number):Promise<boolean>;
+export function ReadRawRegisters(arg1:number,arg2:number,arg3:number):Promise<Record<string, any>>;
+
+export function SaveSavedSerialConfig(arg1:string,arg2:number,arg3:number,arg4:number,arg5:string,arg6:number,arg7:string,arg8:string,arg9:number):Promise<boolean>;
+
+export function SendConsoleFrame(arg1:string,arg2:boolean):Promise<Record<string, any>>;
+
+export function SetBusSlaveIDs(arg1:Array<number>):Promise<boolean>;
+
+export function SetDeviceProfile(arg1:string):Promise<boolean>;
+
+export function SetPollSchedule(arg1:Record<string, number>):Promise<boolean>;
+
+export function SetStateThresholds(arg1:number,arg2:number):Promise<boolean>;
 
 export function StartPolling():Promise<boolean>;
 
 export function StopPolling():Promise<boolean>;
 
-export function UpdateSerialConfig(arg1:string,arg2:number,arg3:number,arg4:number,arg5:string,arg6:number):Promise<boolean>;
+export function UpdateMeterSettings(arg1:number,arg2:number):Promise<boolean>;
+
+export function UpdateSerialConfig(arg1:string,arg2:number,arg3:number,arg4:number,arg5:string,arg6:number,arg7:string,arg8:string,arg9:number):Promise<boolean>;
@@ -2,24 +2,108 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CalibrateByteOrder() {
+  return window['go']['main']['App']['CalibrateByteOrder']();
+}
+
+export function ClearConsoleHistory() {
+  return window['go']['main']['App']['ClearConsoleHistory']();
+}
+
+export function ClearMeterEnergy() {
+  return window['go']['main']['App']['ClearMeterEnergy']();
+}
+
 export function ClearSavedSerialConfig() {
   return window['go']['main']['App']['ClearSavedSerialConfig']();
 }
 
+export function ConfirmByteOrder(arg1) {
+  return window['go']['main']['App']['ConfirmByteOrder'](arg1);
+}
+
 export function GetAvailablePorts() {
   return window['go']['main']['App']['GetAvailablePorts']();
 }
 
+export function GetBusDevices() {
+  return window['go']['main']['App']['GetBusDevices']();
+}
+
+export function GetConnectionState() {
+  return window['go']['main']['App']['GetConnectionState']();
+}
+
+export function GetConsoleHistory() {
+  return window['go']['main']['App']['GetConsoleHistory']();
+}
+
+export function GetDataPoints() {
+  return window['go']['main']['App']['GetDataPoints']();
+}
+
+export function GetDeviceElectricalData(arg1) {
+  return window['go']['main']['App']['GetDeviceElectricalData'](arg1);
+}
+
+export function GetDeviceProfile() {
+  return window['go']['main']['App']['GetDeviceProfile']();
+}
+
+export function GetDeviceProfiles() {
+  return window['go']['main']['App']['GetDeviceProfiles']();
+}
+
+export function GetDeviceStatus() {
+  return window['go']['main']['App']['GetDeviceStatus']();
+}
+
 export function GetElectricalData() {
   return window['go']['main']['App']['GetElectricalData']();
 }
 
+export function GetMeterSettings() {
+  return window['go']['main']['App']['GetMeterSettings']();
+}
+
+export function GetPollSchedule() {
+  return window['go']['main']['App']['GetPollSchedule']();
+}
+
+export function GetThreePhaseData() {
+  return window['go']['main']['App']['GetThreePhaseData']();
+}
+
 export function LoadSavedSerialConfig() {
   return window['go']['main']['App']['LoadSavedSerialConfig']();
 }
 
-export function SaveSavedSerialConfig(arg1, arg2, arg3, arg4, arg5, arg6) {
-  return window['go']['main']['App']['SaveSavedSerialConfig'](arg1, arg2, arg3, arg4, arg5, arg6);
+export function ReadRawRegisters(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ReadRawRegisters'](arg1, arg2, arg3);
+}
+
+export function SaveSavedSerialConfig(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9) {
+  return window['go']['main']['App']['SaveSavedSerialConfig'](arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9);
+}
+
+export function SendConsoleFrame(arg1, arg2) {
+  return window['go']['main']['App']['SendConsoleFrame'](arg1, arg2);
+}
+
+export function SetBusSlaveIDs(arg1) {
+  return window['go']['main']['App']['SetBusSlaveIDs'](arg1);
+}
+
+export function SetDeviceProfile(arg1) {
+  return window['go']['main']['App']['SetDeviceProfile'](arg1);
+}
+
+export function SetPollSchedule(arg1) {
+  return window['go']['main']['App']['SetPollSchedule'](arg1);
+}
+
+export function SetStateThresholds(arg1, arg2) {
+  return window['go']['main']['App']['SetStateThresholds'](arg1, arg2);
 }
 
 export function StartPolling() {
@@ -30,6 +114,10 @@ export function StopPolling() {
   return window['go']['main']['App']['StopPolling']();
 }
 
-export function UpdateSerialConfig(arg1, arg2, arg3, arg4, arg5, arg6) {
-  return window['go']['main']['App']['UpdateSerialConfig'](arg1, arg2, arg3, arg4, arg5, arg6);
+export function UpdateMeterSettings(arg1, arg2) {
+  return window['go']['main']['App']['UpdateMeterSettings'](arg1, arg2);
+}
+
+export function UpdateSerialConfig(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9) {
+  return window['go']['main']['App']['UpdateSerialConfig'](arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9);
 }
@@ -0,0 +1,127 @@
+package modbus
+
+import (
+	"fmt"
+)
+
+// Executor 事务执行器：负责在总线上发送请求并返回校验后的响应
+// 轮询器实现该接口，使客户端请求与轮询共享同一把总线锁
+type Executor interface {
+	Execute(tx Transaction) (*Frame, error)
+}
+
+// Client 通用 Modbus 客户端，覆盖常用功能码
+type Client struct {
+	exec    Executor
+	slaveID byte
+}
+
+// NewClient 创建面向指定从站的客户端
+func NewClient(exec Executor, slaveID byte) *Client {
+	return &Client{exec: exec, slaveID: slaveID}
+}
+
+// SlaveID 返回客户端的从站地址
+func (c *Client) SlaveID() byte {
+	return c.slaveID
+}
+
+// ReadCoils 读线圈 (0x01)
+func (c *Client) ReadCoils(startAddr uint16, quantity uint16) ([]bool, error) {
+	return c.readBits(FunctionReadCoils, startAddr, quantity)
+}
+
+// ReadDiscreteInputs 读离散输入 (0x02)
+func (c *Client) ReadDiscreteInputs(startAddr uint16, quantity uint16) ([]bool, error) {
+	return c.readBits(FunctionReadDiscreteInputs, startAddr, quantity)
+}
+
+// ReadHoldingRegisters 读保持寄存器 (0x03)
+func (c *Client) ReadHoldingRegisters(startAddr uint16, quantity uint16) ([]uint16, error) {
+	return c.readRegisters(FunctionReadHoldingRegisters, startAddr, quantity)
+}
+
+// ReadInputRegisters 读输入寄存器 (0x04)
+func (c *Client) ReadInputRegisters(startAddr uint16, quantity uint16) ([]uint16, error) {
+	return c.readRegisters(FunctionReadInputRegisters, startAddr, quantity)
+}
+
+// WriteSingleCoil 写单个线圈 (0x05)
+func (c *Client) WriteSingleCoil(addr uint16, on bool) error {
+	_, err := c.exec.Execute(NewWriteCoilRequest(c.slaveID, addr, on))
+	return err
+}
+
+// WriteMultipleCoils 写多个线圈 (0x0F)
+func (c *Client) WriteMultipleCoils(startAddr uint16, values []bool) error {
+	if len(values) == 0 || len(values) > MaxWriteCoils {
+		return fmt.Errorf("写线圈数量非法: %d", len(values))
+	}
+	_, err := c.exec.Execute(NewWriteCoilsRequest(c.slaveID, startAddr, values))
+	return err
+}
+
+// WriteSingleRegister 写单个寄存器 (0x06)
+func (c *Client) WriteSingleRegister(addr uint16, value uint16) error {
+	_, err := c.exec.Execute(NewWriteSingleRequest(c.slaveID, addr, value))
+	return err
+}
+
+// WriteMultipleRegisters 写多个寄存器 (0x10)
+func (c *Client) WriteMultipleRegisters(startAddr uint16, values []uint16) error {
+	if len(values) == 0 || len(values) > MaxWriteRegisters {
+		return fmt.Errorf("写寄存器数量非法: %d", len(values))
+	}
+	_, err := c.exec.Execute(NewWriteMultipleRequest(c.slaveID, startAddr, values))
+	return err
+}
+
+// ReadDeviceIdentification 读设备标识 (0x2B/0x0E)，自动跟随 "后续还有" 标志拼接全部对象
+func (c *Client) ReadDeviceIdentification(readCode byte) (*DeviceIdentification, error) {
+	const maxRounds = 16
+
+	result := &DeviceIdentification{ReadDeviceIDCode: readCode, Objects: make(map[byte]string)}
+	objectID := byte(ObjectVendorName)
+	for round := 0; round < maxRounds; round++ {
+		frame, err := c.exec.Execute(NewReadDeviceIDRequest(c.slaveID, readCode, objectID))
+		if err != nil {
+			return nil, err
+		}
+		id, err := ParseDeviceIdentification(frame.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		result.ConformityLevel = id.ConformityLevel
+		for k, v := range id.Objects {
+			result.Objects[k] = v
+		}
+		if !id.MoreFollows || readCode == DeviceIDSpecific {
+			return result, nil
+		}
+		objectID = id.NextObjectID
+	}
+	return nil, fmt.Errorf("设备标识分段过多")
+}
+
+func (c *Client) readBits(function byte, startAddr uint16, quantity uint16) ([]bool, error) {
+	if quantity == 0 || quantity > MaxReadBits {
+		return nil, fmt.Errorf("读取数量非法: %d", quantity)
+	}
+	frame, err := c.exec.Execute(NewReadFunctionRequest(c.slaveID, function, startAddr, quantity))
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBits(frame.Data, quantity), nil
+}
+
+func (c *Client) readRegisters(function byte, startAddr uint16, quantity uint16) ([]uint16, error) {
+	if quantity == 0 || quantity > MaxReadRegisters {
+		return nil, fmt.Errorf("读取数量非法: %d", quantity)
+	}
+	frame, err := c.exec.Execute(NewReadFunctionRequest(c.slaveID, function, startAddr, quantity))
+	if err != nil {
+		return nil, err
+	}
+	return DecodeRegisters(frame.Data), nil
+}
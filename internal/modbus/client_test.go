@@ -0,0 +1,89 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// responderExecutor 将请求交给应答函数，模拟总线上的从站
+type responderExecutor struct {
+	respond func(request []byte) []byte
+	count   int
+}
+
+func (e *responderExecutor) Execute(tx Transaction) (*Frame, error) {
+	e.count++
+	return tx.ParseResponse(e.respond(tx.Encode()))
+}
+
+func TestClient_ReadAndWrite(t *testing.T) {
+	exec := &responderExecutor{respond: func(req []byte) []byte {
+		switch req[1] {
+		case FunctionReadCoils:
+			return buildResponse(req[0], req[1], []byte{0x05})
+		case FunctionReadInputRegisters:
+			return buildResponse(req[0], req[1], []byte{0x12, 0x34})
+		case FunctionWriteSingleCoil, FunctionWriteSingleRegister:
+			return req
+		case FunctionWriteMultipleCoils, FunctionWriteMultipleRegisters:
+			resp := append([]byte(nil), req[:6]...)
+			return binary.LittleEndian.AppendUint16(resp, CalculateCRC16(resp))
+		}
+		return nil
+	}}
+	c := NewClient(exec, 0x05)
+
+	bits, err := c.ReadCoils(0, 3)
+	if err != nil || len(bits) != 3 || !bits[0] || bits[1] || !bits[2] {
+		t.Fatalf("ReadCoils: got %v, %v", bits, err)
+	}
+	regs, err := c.ReadInputRegisters(0x0100, 1)
+	if err != nil || len(regs) != 1 || regs[0] != 0x1234 {
+		t.Fatalf("ReadInputRegisters: got %v, %v", regs, err)
+	}
+	if err := c.WriteSingleCoil(1, true); err != nil {
+		t.Fatalf("WriteSingleCoil: %v", err)
+	}
+	if err := c.WriteMultipleCoils(0, []bool{true, false}); err != nil {
+		t.Fatalf("WriteMultipleCoils: %v", err)
+	}
+	if err := c.WriteSingleRegister(1, 2); err != nil {
+		t.Fatalf("WriteSingleRegister: %v", err)
+	}
+	if err := c.WriteMultipleRegisters(1, []uint16{2, 3}); err != nil {
+		t.Fatalf("WriteMultipleRegisters: %v", err)
+	}
+
+	// 数量非法时不发起请求
+	before := exec.count
+	if _, err := c.ReadHoldingRegisters(0, 0); err == nil {
+		t.Fatalf("expected error for zero quantity")
+	}
+	if _, err := c.ReadDiscreteInputs(0, MaxReadBits+1); err == nil {
+		t.Fatalf("expected error for too many bits")
+	}
+	if exec.count != before {
+		t.Fatalf("invalid requests should not reach the bus")
+	}
+}
+
+func TestClient_ReadDeviceIdentificationMoreFollows(t *testing.T) {
+	exec := &responderExecutor{respond: func(req []byte) []byte {
+		// 首段返回厂商并提示后续还有，第二段返回产品代码
+		if req[4] == ObjectVendorName {
+			return buildDeviceIDResponse(req[0], req[3], true, ObjectProductCode, [][2]string{{"\x00", "CHINT"}})
+		}
+		return buildDeviceIDResponse(req[0], req[3], false, 0, [][2]string{{"\x01", "DDSU666"}})
+	}}
+
+	id, err := NewClient(exec, 0x01).ReadDeviceIdentification(DeviceIDBasic)
+	if err != nil {
+		t.Fatalf("ReadDeviceIdentification failed: %v", err)
+	}
+	if exec.count != 2 {
+		t.Fatalf("expected 2 requests, got %d", exec.count)
+	}
+	if id.Objects[ObjectVendorName] != "CHINT" || id.Objects[ObjectProductCode] != "DDSU666" {
+		t.Fatalf("objects mismatch: %#v", id.Objects)
+	}
+}
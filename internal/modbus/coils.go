@@ -0,0 +1,153 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// 线圈相关常量
+const (
+	MaxReadBits   = 2000 // 0x01/0x02 单帧最多读取的位数
+	MaxWriteCoils = 1968 // 0x0F 单帧最多写入的线圈数
+	coilOn        = 0xFF00
+	coilOff       = 0x0000
+)
+
+// BuildWriteSingleCoilFrame 构造写单个线圈帧 (0x05)
+func BuildWriteSingleCoilFrame(slaveID byte, addr uint16, on bool) []byte {
+	frame := make([]byte, 8)
+	frame[0] = slaveID
+	frame[1] = FunctionWriteSingleCoil
+	binary.BigEndian.PutUint16(frame[2:4], addr)
+	binary.BigEndian.PutUint16(frame[4:6], coilValue(on))
+
+	crc := CalculateCRC16(frame[:6])
+	binary.LittleEndian.PutUint16(frame[6:8], crc)
+
+	return frame
+}
+
+// BuildWriteMultipleCoilsFrame 构造写多个线圈帧 (0x0F)
+func BuildWriteMultipleCoilsFrame(slaveID byte, startAddr uint16, values []bool) ([]byte, error) {
+	if len(values) == 0 || len(values) > MaxWriteCoils {
+		return nil, fmt.Errorf("写线圈数量非法: %d", len(values))
+	}
+
+	packed := EncodeBits(values)
+	frame := make([]byte, 7, 7+len(packed)+2)
+	frame[0] = slaveID
+	frame[1] = FunctionWriteMultipleCoils
+	binary.BigEndian.PutUint16(frame[2:4], startAddr)
+	binary.BigEndian.PutUint16(frame[4:6], uint16(len(values)))
+	frame[6] = byte(len(packed))
+	frame = append(frame, packed...)
+
+	crc := CalculateCRC16(frame)
+	frame = binary.LittleEndian.AppendUint16(frame, crc)
+
+	return frame, nil
+}
+
+// EncodeBits 按 Modbus 规则打包位：每字节低位在前
+func EncodeBits(values []bool) []byte {
+	packed := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return packed
+}
+
+// DecodeBits 解包 0x01/0x02 响应中的位，quantity 为请求的位数
+func DecodeBits(data []byte, quantity uint16) []bool {
+	values := make([]bool, 0, quantity)
+	for i := 0; i < int(quantity) && i/8 < len(data); i++ {
+		values = append(values, data[i/8]&(1<<(i%8)) != 0)
+	}
+	return values
+}
+
+// DecodeRegisters 将 0x03/0x04 响应数据转换为寄存器值
+func DecodeRegisters(data []byte) []uint16 {
+	values := make([]uint16, len(data)/2)
+	for i := range values {
+		values[i] = binary.BigEndian.Uint16(data[i*2:])
+	}
+	return values
+}
+
+func coilValue(on bool) uint16 {
+	if on {
+		return coilOn
+	}
+	return coilOff
+}
+
+// WriteCoilRequest 写单个线圈事务 (0x05)
+type WriteCoilRequest struct {
+	SlaveID byte
+	Addr    uint16
+	On      bool
+}
+
+// NewWriteCoilRequest 创建写单个线圈事务
+func NewWriteCoilRequest(slaveID byte, addr uint16, on bool) *WriteCoilRequest {
+	return &WriteCoilRequest{SlaveID: slaveID, Addr: addr, On: on}
+}
+
+// Encode 构造请求帧
+func (r *WriteCoilRequest) Encode() []byte {
+	return BuildWriteSingleCoilFrame(r.SlaveID, r.Addr, r.On)
+}
+
+// ParseResponse 解析响应并校验回显：正常响应应与请求完全一致
+func (r *WriteCoilRequest) ParseResponse(data []byte) (*Frame, error) {
+	frame, err := validateHeader(data, r.SlaveID, FunctionWriteSingleCoil)
+	if err != nil {
+		return frame, err
+	}
+	if got := binary.BigEndian.Uint16(frame.Data[0:2]); got != r.Addr {
+		return nil, &EchoMismatchError{Field: "地址", Want: r.Addr, Got: got}
+	}
+	if want, got := coilValue(r.On), binary.BigEndian.Uint16(frame.Data[2:4]); got != want {
+		return nil, &EchoMismatchError{Field: "值", Want: want, Got: got}
+	}
+	return frame, nil
+}
+
+// WriteCoilsRequest 写多个线圈事务 (0x0F)
+type WriteCoilsRequest struct {
+	SlaveID   byte
+	StartAddr uint16
+	Values    []bool
+}
+
+// NewWriteCoilsRequest 创建写多个线圈事务
+func NewWriteCoilsRequest(slaveID byte, startAddr uint16, values []bool) *WriteCoilsRequest {
+	return &WriteCoilsRequest{SlaveID: slaveID, StartAddr: startAddr, Values: values}
+}
+
+// Encode 构造请求帧，线圈数量非法时返回 nil
+func (r *WriteCoilsRequest) Encode() []byte {
+	frame, err := BuildWriteMultipleCoilsFrame(r.SlaveID, r.StartAddr, r.Values)
+	if err != nil {
+		return nil
+	}
+	return frame
+}
+
+// ParseResponse 解析响应并校验回显的起始地址与数量
+func (r *WriteCoilsRequest) ParseResponse(data []byte) (*Frame, error) {
+	frame, err := validateHeader(data, r.SlaveID, FunctionWriteMultipleCoils)
+	if err != nil {
+		return frame, err
+	}
+	if got := binary.BigEndian.Uint16(frame.Data[0:2]); got != r.StartAddr {
+		return nil, &EchoMismatchError{Field: "地址", Want: r.StartAddr, Got: got}
+	}
+	if got := binary.BigEndian.Uint16(frame.Data[2:4]); got != uint16(len(r.Values)) {
+		return nil, &EchoMismatchError{Field: "数量", Want: uint16(len(r.Values)), Got: got}
+	}
+	return frame, nil
+}
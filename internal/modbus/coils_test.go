@@ -0,0 +1,112 @@
+package modbus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestBuildReadFunctionFrame(t *testing.T) {
+	for _, fn := range []byte{FunctionReadCoils, FunctionReadDiscreteInputs, FunctionReadInputRegisters} {
+		frame := BuildReadFunctionFrame(0x01, fn, 0x0013, 0x0025)
+		want := []byte{0x01, fn, 0x00, 0x13, 0x00, 0x25}
+		if !bytes.Equal(frame[:6], want) {
+			t.Fatalf("fn %02X: frame mismatch: got % X want % X", fn, frame[:6], want)
+		}
+		if got := binary.LittleEndian.Uint16(frame[6:8]); got != CalculateCRC16(frame[:6]) {
+			t.Fatalf("fn %02X: crc mismatch", fn)
+		}
+	}
+}
+
+func TestParseResponse_ReadCoils(t *testing.T) {
+	// Modbus 规范示例：读 19 个线圈 (20-38)，响应 CD 6B 05
+	resp := buildResponse(0x01, FunctionReadCoils, []byte{0xCD, 0x6B, 0x05})
+	req := NewReadFunctionRequest(0x01, FunctionReadCoils, 0x0013, 19)
+
+	frame, err := req.ParseResponse(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bits := DecodeBits(frame.Data, 19)
+	if len(bits) != 19 {
+		t.Fatalf("bit count: got %d want 19", len(bits))
+	}
+	// 0xCD = 1100 1101，低位在前
+	want := []bool{true, false, true, true, false, false, true, true}
+	for i, w := range want {
+		if bits[i] != w {
+			t.Fatalf("bit %d: got %v want %v", i, bits[i], w)
+		}
+	}
+	if !bits[16] || bits[17] || !bits[18] {
+		t.Fatalf("last byte bits mismatch: %v", bits[16:])
+	}
+}
+
+func TestParseResponse_ReadCoilsByteCountMismatch(t *testing.T) {
+	resp := buildResponse(0x01, FunctionReadDiscreteInputs, []byte{0xAC, 0xDB})
+	req := NewReadFunctionRequest(0x01, FunctionReadDiscreteInputs, 0x00C4, 22)
+
+	var countErr *ByteCountError
+	if _, err := req.ParseResponse(resp); !errors.As(err, &countErr) || countErr.Want != 3 {
+		t.Fatalf("expected ByteCountError want 3, got %v", err)
+	}
+}
+
+func TestParseResponse_ReadInputRegisters(t *testing.T) {
+	resp := buildResponse(0x01, FunctionReadInputRegisters, []byte{0x00, 0x0A, 0x43, 0x5C})
+	req := NewReadFunctionRequest(0x01, FunctionReadInputRegisters, 0x0008, 2)
+
+	frame, err := req.ParseResponse(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	regs := DecodeRegisters(frame.Data)
+	if len(regs) != 2 || regs[0] != 0x000A || regs[1] != 0x435C {
+		t.Fatalf("registers mismatch: %04X", regs)
+	}
+}
+
+func TestBuildWriteSingleCoilFrame(t *testing.T) {
+	frame := BuildWriteSingleCoilFrame(0x11, 0x00AC, true)
+	want := []byte{0x11, 0x05, 0x00, 0xAC, 0xFF, 0x00}
+	if !bytes.Equal(frame[:6], want) {
+		t.Fatalf("frame mismatch: got % X want % X", frame[:6], want)
+	}
+
+	req := NewWriteCoilRequest(0x11, 0x00AC, true)
+	if _, err := req.ParseResponse(frame); err != nil {
+		t.Fatalf("echo response rejected: %v", err)
+	}
+
+	var echoErr *EchoMismatchError
+	off := BuildWriteSingleCoilFrame(0x11, 0x00AC, false)
+	if _, err := req.ParseResponse(off); !errors.As(err, &echoErr) {
+		t.Fatalf("expected EchoMismatchError, got %v", err)
+	}
+}
+
+func TestBuildWriteMultipleCoilsFrame(t *testing.T) {
+	// Modbus 规范示例：从 20 开始写 10 个线圈，数据 CD 01
+	values := []bool{true, false, true, true, false, false, true, true, true, false}
+	frame, err := BuildWriteMultipleCoilsFrame(0x01, 0x0013, values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []byte{0x01, 0x0F, 0x00, 0x13, 0x00, 0x0A, 0x02, 0xCD, 0x01}
+	if !bytes.Equal(frame[:len(want)], want) {
+		t.Fatalf("frame mismatch: got % X want % X", frame, want)
+	}
+
+	if _, err := BuildWriteMultipleCoilsFrame(0x01, 0, nil); err == nil {
+		t.Fatalf("expected error for empty coils")
+	}
+
+	resp := []byte{0x01, FunctionWriteMultipleCoils, 0x00, 0x13, 0x00, 0x0A}
+	resp = binary.LittleEndian.AppendUint16(resp, CalculateCRC16(resp))
+	if _, err := NewWriteCoilsRequest(0x01, 0x0013, values).ParseResponse(resp); err != nil {
+		t.Fatalf("valid response rejected: %v", err)
+	}
+}
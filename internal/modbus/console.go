@@ -0,0 +1,214 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// FrameField 帧解析结果中的一个字段
+type FrameField struct {
+	Name  string // 字段名称
+	Hex   string // 字段原始字节
+	Value string // 字段含义
+}
+
+// ParseHex 解析手工输入的十六进制帧
+// 字节之间可用空格、逗号、冒号或短横线分隔，允许 0x 前缀，也可连续书写如 "0C0320000002"
+func ParseHex(text string) ([]byte, error) {
+	var digits strings.Builder
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == ',' || r == ':' || r == '-' || r == '\t' || r == '\n' || r == '\r'
+	})
+	for _, f := range fields {
+		f = strings.TrimPrefix(strings.TrimPrefix(f, "0x"), "0X")
+		if len(f)%2 != 0 {
+			if len(fields) == 1 {
+				return nil, fmt.Errorf("十六进制字符数必须为偶数: %s", f)
+			}
+			f = "0" + f // 以分隔符分开时允许单个字符表示一个字节，如 "C 3"
+		}
+		digits.WriteString(f)
+	}
+	data, err := hex.DecodeString(digits.String())
+	if err != nil {
+		return nil, fmt.Errorf("十六进制格式无效: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("帧为空")
+	}
+	return data, nil
+}
+
+// FormatHex 以空格分隔的大写十六进制输出
+func FormatHex(data []byte) string {
+	parts := make([]string, len(data))
+	for i, b := range data {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, " ")
+}
+
+// AppendCRC 返回追加 CRC16（低字节在前）后的新帧
+func AppendCRC(frame []byte) []byte {
+	out := make([]byte, len(frame), len(frame)+2)
+	copy(out, frame)
+	return binary.LittleEndian.AppendUint16(out, CalculateCRC16(frame))
+}
+
+// CheckCRC 检查帧末尾两字节是否为正确的 CRC16
+func CheckCRC(frame []byte) bool {
+	if len(frame) < 4 {
+		return false
+	}
+	n := len(frame) - 2
+	return binary.LittleEndian.Uint16(frame[n:]) == CalculateCRC16(frame[:n])
+}
+
+// FunctionName 返回功能码名称
+func FunctionName(function byte) string {
+	switch function &^ 0x80 {
+	case FunctionReadCoils:
+		return "读线圈"
+	case FunctionReadDiscreteInputs:
+		return "读离散输入"
+	case FunctionReadHoldingRegisters:
+		return "读保持寄存器"
+	case FunctionReadInputRegisters:
+		return "读输入寄存器"
+	case FunctionWriteSingleCoil:
+		return "写单个线圈"
+	case FunctionWriteSingleRegister:
+		return "写单个寄存器"
+	case FunctionWriteMultipleCoils:
+		return "写多个线圈"
+	case FunctionWriteMultipleRegisters:
+		return "写多个寄存器"
+	case FunctionEncapsulatedInterface:
+		return "封装接口 (MEI)"
+	default:
+		return "未知功能码"
+	}
+}
+
+// DescribeRequest 逐字段解析请求帧（含 CRC），无法识别的部分归入“数据”字段
+func DescribeRequest(frame []byte) []FrameField {
+	fields, body := describeHeader(frame)
+	if body == nil {
+		return fields
+	}
+
+	switch frame[1] {
+	case FunctionReadCoils, FunctionReadDiscreteInputs,
+		FunctionReadHoldingRegisters, FunctionReadInputRegisters:
+		body = describeWord(&fields, body, "起始地址", true)
+		body = describeWord(&fields, body, "数量", false)
+	case FunctionWriteSingleCoil, FunctionWriteSingleRegister:
+		body = describeWord(&fields, body, "地址", true)
+		body = describeWord(&fields, body, "值", true)
+	case FunctionWriteMultipleCoils, FunctionWriteMultipleRegisters:
+		body = describeWord(&fields, body, "起始地址", true)
+		body = describeWord(&fields, body, "数量", false)
+		if len(body) > 0 {
+			fields = append(fields, FrameField{Name: "字节数", Hex: FormatHex(body[:1]), Value: fmt.Sprint(body[0])})
+			body = body[1:]
+		}
+		if frame[1] == FunctionWriteMultipleRegisters {
+			body = describeRegisters(&fields, body)
+		}
+	}
+	return describeTail(fields, body, frame)
+}
+
+// DescribeResponse 逐字段解析响应帧（含 CRC），无法识别的部分归入“数据”字段
+func DescribeResponse(frame []byte) []FrameField {
+	fields, body := describeHeader(frame)
+	if body == nil {
+		return fields
+	}
+
+	function := frame[1]
+	switch {
+	case function&0x80 != 0:
+		if len(body) > 0 {
+			e := &ExceptionError{Function: function &^ 0x80, Code: body[0]}
+			fields = append(fields, FrameField{Name: "异常码", Hex: FormatHex(body[:1]), Value: e.Meaning()})
+			body = body[1:]
+		}
+	case function == FunctionReadHoldingRegisters || function == FunctionReadInputRegisters:
+		if len(body) > 0 {
+			fields = append(fields, FrameField{Name: "字节数", Hex: FormatHex(body[:1]), Value: fmt.Sprint(body[0])})
+			body = describeRegisters(&fields, body[1:])
+		}
+	case function == FunctionReadCoils || function == FunctionReadDiscreteInputs:
+		if len(body) > 0 {
+			fields = append(fields, FrameField{Name: "字节数", Hex: FormatHex(body[:1]), Value: fmt.Sprint(body[0])})
+			body = body[1:]
+		}
+	case function == FunctionWriteSingleCoil || function == FunctionWriteSingleRegister:
+		body = describeWord(&fields, body, "地址", true)
+		body = describeWord(&fields, body, "值", true)
+	case function == FunctionWriteMultipleCoils || function == FunctionWriteMultipleRegisters:
+		body = describeWord(&fields, body, "起始地址", true)
+		body = describeWord(&fields, body, "数量", false)
+	}
+	return describeTail(fields, body, frame)
+}
+
+// describeHeader 解析从站地址与功能码，返回去掉 CRC 后的剩余部分；帧过短时 body 为 nil
+func describeHeader(frame []byte) ([]FrameField, []byte) {
+	var fields []FrameField
+	if len(frame) > 0 {
+		fields = append(fields, FrameField{Name: "从站地址", Hex: FormatHex(frame[:1]), Value: fmt.Sprint(frame[0])})
+	}
+	if len(frame) > 1 {
+		value := FunctionName(frame[1])
+		if frame[1]&0x80 != 0 {
+			value += "（异常响应）"
+		}
+		fields = append(fields, FrameField{Name: "功能码", Hex: FormatHex(frame[1:2]), Value: value})
+	}
+	if len(frame) < 4 {
+		if len(frame) > 2 {
+			fields = append(fields, FrameField{Name: "数据", Hex: FormatHex(frame[2:])})
+		}
+		return fields, nil
+	}
+	return fields, frame[2 : len(frame)-2]
+}
+
+// describeWord 解析一个 16 位字段
+func describeWord(fields *[]FrameField, body []byte, name string, asHex bool) []byte {
+	if len(body) < 2 {
+		return body
+	}
+	v := binary.BigEndian.Uint16(body)
+	value := fmt.Sprint(v)
+	if asHex {
+		value = fmt.Sprintf("0x%04X (%d)", v, v)
+	}
+	*fields = append(*fields, FrameField{Name: name, Hex: FormatHex(body[:2]), Value: value})
+	return body[2:]
+}
+
+// describeRegisters 逐个解析寄存器值
+func describeRegisters(fields *[]FrameField, body []byte) []byte {
+	for i := 0; len(body) >= 2; i++ {
+		body = describeWord(fields, body, fmt.Sprintf("寄存器[%d]", i), true)
+	}
+	return body
+}
+
+// describeTail 追加剩余数据与 CRC 校验结果
+func describeTail(fields []FrameField, body []byte, frame []byte) []FrameField {
+	if len(body) > 0 {
+		fields = append(fields, FrameField{Name: "数据", Hex: FormatHex(body)})
+	}
+	n := len(frame) - 2
+	verdict := "正确"
+	if !CheckCRC(frame) {
+		verdict = fmt.Sprintf("错误（应为 %s）", FormatHex(binary.LittleEndian.AppendUint16(nil, CalculateCRC16(frame[:n]))))
+	}
+	return append(fields, FrameField{Name: "CRC", Hex: FormatHex(frame[n:]), Value: verdict})
+}
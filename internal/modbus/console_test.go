@@ -0,0 +1,69 @@
+package modbus
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseHex(t *testing.T) {
+	want := []byte{0x0C, 0x03, 0x20, 0x00, 0x00, 0x02}
+	for _, text := range []string{"0C 03 20 00 00 02", "0c0320000002", "0x0C,0x03,0x20,0x00,0x00,0x02", "C 3 20 0 0 2"} {
+		got, err := ParseHex(text)
+		if err != nil {
+			t.Fatalf("%q: %v", text, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("%q: got % X", text, got)
+		}
+	}
+	for _, text := range []string{"", "0C0", "0C 0G"} {
+		if _, err := ParseHex(text); err == nil {
+			t.Fatalf("%q: expected error", text)
+		}
+	}
+}
+
+func TestAppendAndCheckCRC(t *testing.T) {
+	frame := AppendCRC([]byte{0x0C, 0x03, 0x20, 0x00, 0x00, 0x02})
+	if !bytes.Equal(frame, BuildReadFrame(0x0C, 0x2000, 2)) {
+		t.Fatalf("AppendCRC got % X", frame)
+	}
+	if !CheckCRC(frame) {
+		t.Fatalf("CRC should be valid")
+	}
+	frame[7] ^= 0xFF
+	if CheckCRC(frame) || CheckCRC([]byte{0x0C, 0x03}) {
+		t.Fatalf("CRC should be invalid")
+	}
+}
+
+func TestDescribeFrames(t *testing.T) {
+	req := DescribeRequest(BuildReadFrame(0x0C, 0x2000, 2))
+	names := []string{"从站地址", "功能码", "起始地址", "数量", "CRC"}
+	if len(req) != len(names) {
+		t.Fatalf("request fields got %+v", req)
+	}
+	for i, name := range names {
+		if req[i].Name != name {
+			t.Fatalf("field %d got %q want %q", i, req[i].Name, name)
+		}
+	}
+	if req[2].Value != "0x2000 (8192)" || req[4].Value != "正确" {
+		t.Fatalf("unexpected request fields: %+v", req)
+	}
+
+	resp := DescribeResponse(AppendCRC([]byte{0x0C, 0x03, 0x04, 0x43, 0x5C, 0x80, 0x00}))
+	if len(resp) != 6 || resp[3].Name != "寄存器[0]" || resp[4].Hex != "80 00" {
+		t.Fatalf("response fields got %+v", resp)
+	}
+
+	exc := AppendCRC([]byte{0x0C, 0x83, ExceptionIllegalDataAddr})
+	exc[len(exc)-1] ^= 0xFF
+	fields := DescribeResponse(exc)
+	if fields[2].Name != "异常码" || fields[2].Value != (&ExceptionError{Code: ExceptionIllegalDataAddr}).Meaning() {
+		t.Fatalf("exception fields got %+v", fields)
+	}
+	if last := fields[len(fields)-1]; last.Name != "CRC" || last.Value == "正确" {
+		t.Fatalf("expected CRC error verdict, got %+v", last)
+	}
+}
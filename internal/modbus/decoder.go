@@ -0,0 +1,118 @@
+package modbus
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// 合法从站地址范围
+const (
+	MinSlaveID = 0x01
+	MaxSlaveID = 0xF7
+)
+
+// Decoder RTU 流式帧解码器
+// 逐段接收字节，在流中搜索 "合法从站地址 + 已知功能码 + CRC 正确" 的完整帧，
+// 丢弃线路噪声以及半双工适配器回显的本端请求
+type Decoder struct {
+	buf       []byte
+	echo      []byte
+	discarded int
+}
+
+// NewDecoder 创建解码器
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// ExpectEcho 记录本端刚发送的请求，流中出现时将其丢弃
+// 写单个线圈/寄存器 (0x05/0x06) 的正常响应与请求完全相同，无法与回显区分，此时不做剔除
+func (d *Decoder) ExpectEcho(request []byte) {
+	d.echo = d.echo[:0]
+	if len(request) < 2 || request[1] == FunctionWriteSingleCoil || request[1] == FunctionWriteSingleRegister {
+		return
+	}
+	d.echo = append(d.echo, request...)
+}
+
+// Feed 追加收到的字节
+func (d *Decoder) Feed(data []byte) {
+	d.buf = append(d.buf, data...)
+}
+
+// Next 取出下一帧完整 RTU 帧（含 CRC），没有完整帧时返回 nil
+func (d *Decoder) Next() []byte {
+	d.stripEcho()
+
+	firstPending := -1
+	for i := 0; i+2 <= len(d.buf); i++ {
+		if d.buf[i] < MinSlaveID || d.buf[i] > MaxSlaveID {
+			continue
+		}
+		frameLen, ok := ExpectedResponseLength(d.buf[i:])
+		if !ok {
+			// 长度未知：可能是数据不足（字节计数尚未到达）
+			if len(d.buf)-i < 3 && firstPending < 0 {
+				firstPending = i
+			}
+			continue
+		}
+		if len(d.buf)-i < frameLen {
+			// 候选帧尚不完整，继续寻找后续位置是否已有完整帧
+			if firstPending < 0 {
+				firstPending = i
+			}
+			continue
+		}
+
+		candidate := d.buf[i : i+frameLen]
+		crc := binary.LittleEndian.Uint16(candidate[frameLen-2:])
+		if crc != CalculateCRC16(candidate[:frameLen-2]) {
+			continue
+		}
+
+		frame := append([]byte(nil), candidate...)
+		d.discarded += i
+		d.buf = d.buf[i+frameLen:]
+		return frame
+	}
+
+	// 没有完整帧：丢弃首个候选位置之前的垃圾字节
+	drop := len(d.buf)
+	if firstPending >= 0 {
+		drop = firstPending
+	} else if drop > 0 && d.buf[drop-1] >= MinSlaveID && d.buf[drop-1] <= MaxSlaveID {
+		drop-- // 末尾单字节可能是下一帧的从站地址
+	}
+	d.discarded += drop
+	d.buf = d.buf[drop:]
+	return nil
+}
+
+// stripEcho 丢弃位于缓冲起始处的本端请求回显
+func (d *Decoder) stripEcho() {
+	if len(d.echo) == 0 || len(d.buf) < len(d.echo) {
+		return
+	}
+	if idx := bytes.Index(d.buf, d.echo); idx >= 0 {
+		d.discarded += idx + len(d.echo)
+		d.buf = d.buf[idx+len(d.echo):]
+		d.echo = d.echo[:0]
+	}
+}
+
+// Buffered 返回尚未组成完整帧的字节数
+func (d *Decoder) Buffered() int {
+	return len(d.buf)
+}
+
+// Discarded 返回累计丢弃的字节数（噪声与回显）
+func (d *Decoder) Discarded() int {
+	return d.discarded
+}
+
+// Reset 清空缓冲（帧间静默后丢弃不完整的残帧）
+func (d *Decoder) Reset() {
+	d.discarded += len(d.buf)
+	d.buf = d.buf[:0]
+}
@@ -0,0 +1,112 @@
+package modbus
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecoder_Resync(t *testing.T) {
+	resp := buildResponse(0x0C, FunctionReadHoldingRegisters, []byte{0x43, 0x5C, 0x80, 0x00})
+	request := BuildReadFrame(0x0C, 0x2000, 2)
+
+	cases := []struct {
+		name   string
+		chunks [][]byte
+		echo   []byte
+	}{
+		{"clean frame", [][]byte{resp}, nil},
+		{"leading noise", [][]byte{{0x00, 0xFF, 0x13}, resp}, nil},
+		{"noise resembling header", [][]byte{{0x0C, 0x03, 0x7F, 0x55}, resp}, nil},
+		{"split across reads", [][]byte{resp[:2], resp[2:5], resp[5:]}, nil},
+		{"half-duplex echo", [][]byte{request, resp}, request},
+		{"echo and response in one read", [][]byte{append(append([]byte{}, request...), resp...)}, request},
+	}
+	for _, tc := range cases {
+		d := NewDecoder()
+		if tc.echo != nil {
+			d.ExpectEcho(tc.echo)
+		}
+		var got []byte
+		for _, chunk := range tc.chunks {
+			d.Feed(chunk)
+			if frame := d.Next(); frame != nil {
+				got = frame
+			}
+		}
+		if !bytes.Equal(got, resp) {
+			t.Errorf("%s: got % X want % X", tc.name, got, resp)
+		}
+		if d.Buffered() != 0 {
+			t.Errorf("%s: expected empty buffer, %d bytes left", tc.name, d.Buffered())
+		}
+	}
+}
+
+func TestDecoder_ExceptionFrame(t *testing.T) {
+	frame := []byte{0x01, FunctionReadHoldingRegisters | 0x80, 0x02}
+	crc := CalculateCRC16(frame)
+	frame = append(frame, byte(crc), byte(crc>>8))
+
+	d := NewDecoder()
+	d.Feed(append([]byte{0xAA}, frame...))
+	got := d.Next()
+	if !bytes.Equal(got, frame) {
+		t.Fatalf("got % X want % X", got, frame)
+	}
+	if d.Discarded() != 1 {
+		t.Fatalf("expected 1 discarded byte, got %d", d.Discarded())
+	}
+}
+
+func TestDecoder_IncompleteAndCorrupt(t *testing.T) {
+	resp := buildResponse(0x01, FunctionReadHoldingRegisters, []byte{0x43, 0x5C, 0x80, 0x00})
+
+	d := NewDecoder()
+	d.Feed(resp[:len(resp)-1])
+	if frame := d.Next(); frame != nil {
+		t.Fatalf("expected nil for incomplete frame, got % X", frame)
+	}
+
+	corrupt := append([]byte(nil), resp...)
+	corrupt[4] ^= 0xFF
+	d.Reset()
+	d.Feed(corrupt)
+	if frame := d.Next(); frame != nil {
+		t.Fatalf("expected nil for corrupt frame, got % X", frame)
+	}
+
+	// 损坏帧之后的正确帧仍能被识别
+	d.Feed(resp)
+	if frame := d.Next(); !bytes.Equal(frame, resp) {
+		t.Fatalf("expected frame after corrupt one, got % X", frame)
+	}
+}
+
+func TestDecoder_MultipleFrames(t *testing.T) {
+	a := buildResponse(0x01, FunctionReadHoldingRegisters, []byte{0x00, 0x01})
+	b := buildResponse(0x02, FunctionReadHoldingRegisters, []byte{0x00, 0x02})
+
+	d := NewDecoder()
+	d.Feed(append(append([]byte{}, a...), b...))
+	if frame := d.Next(); !bytes.Equal(frame, a) {
+		t.Fatalf("first frame: got % X", frame)
+	}
+	if frame := d.Next(); !bytes.Equal(frame, b) {
+		t.Fatalf("second frame: got % X", frame)
+	}
+	if frame := d.Next(); frame != nil {
+		t.Fatalf("expected no more frames, got % X", frame)
+	}
+}
+
+func TestDecoder_WriteSingleResponseNotTreatedAsEcho(t *testing.T) {
+	// 0x06 的正常响应即请求回显，不能被当作本端回显丢弃
+	request := BuildWriteSingleRegisterFrame(0x0C, 0x0006, 0x0011)
+
+	d := NewDecoder()
+	d.ExpectEcho(request)
+	d.Feed(request)
+	if frame := d.Next(); !bytes.Equal(frame, request) {
+		t.Fatalf("expected write response, got % X", frame)
+	}
+}
@@ -0,0 +1,120 @@
+package modbus
+
+import (
+	"fmt"
+)
+
+// MEI 类型与设备标识读取码
+const (
+	MEIReadDeviceIdentification = 0x0E
+
+	DeviceIDBasic    = 0x01 // 基本标识（厂商、产品代码、版本）
+	DeviceIDRegular  = 0x02 // 常规标识
+	DeviceIDExtended = 0x03 // 扩展标识
+	DeviceIDSpecific = 0x04 // 读取单个对象
+)
+
+// 基本设备标识对象ID
+const (
+	ObjectVendorName         = 0x00
+	ObjectProductCode        = 0x01
+	ObjectMajorMinorRevision = 0x02
+	ObjectVendorURL          = 0x03
+	ObjectProductName        = 0x04
+	ObjectModelName          = 0x05
+)
+
+// DeviceIdentification 0x2B/0x0E 响应内容
+type DeviceIdentification struct {
+	ReadDeviceIDCode byte
+	ConformityLevel  byte
+	MoreFollows      bool
+	NextObjectID     byte
+	Objects          map[byte]string
+}
+
+// BuildReadDeviceIDFrame 构造读设备标识帧 (0x2B/0x0E)
+func BuildReadDeviceIDFrame(slaveID byte, readCode byte, objectID byte) []byte {
+	frame := []byte{slaveID, FunctionEncapsulatedInterface, MEIReadDeviceIdentification, readCode, objectID}
+	crc := CalculateCRC16(frame)
+	return append(frame, byte(crc), byte(crc>>8))
+}
+
+// deviceIDResponseLength 遍历对象列表推算 0x2B 响应的完整帧长
+func deviceIDResponseLength(data []byte) (int, bool) {
+	// 从站ID, 功能码, MEI, 读取码, 一致性等级, 后续标志, 下一对象ID, 对象数量
+	const headerLen = 8
+	if len(data) < headerLen {
+		return 0, false
+	}
+
+	pos := headerLen
+	for i := 0; i < int(data[7]); i++ {
+		if len(data) < pos+2 {
+			return 0, false
+		}
+		pos += 2 + int(data[pos+1])
+	}
+	return pos + 2, true
+}
+
+// ParseDeviceIdentification 解析 0x2B 响应数据区（Frame.Data，从 MEI 类型开始）
+func ParseDeviceIdentification(data []byte) (*DeviceIdentification, error) {
+	if len(data) < 6 {
+		return nil, fmt.Errorf("设备标识响应长度不足")
+	}
+	if data[0] != MEIReadDeviceIdentification {
+		return nil, fmt.Errorf("MEI类型不匹配: %02X", data[0])
+	}
+
+	id := &DeviceIdentification{
+		ReadDeviceIDCode: data[1],
+		ConformityLevel:  data[2],
+		MoreFollows:      data[3] == 0xFF,
+		NextObjectID:     data[4],
+		Objects:          make(map[byte]string),
+	}
+
+	pos := 6
+	for i := 0; i < int(data[5]); i++ {
+		if len(data) < pos+2 || len(data) < pos+2+int(data[pos+1]) {
+			return nil, fmt.Errorf("设备标识对象长度不足")
+		}
+		objectID, length := data[pos], int(data[pos+1])
+		id.Objects[objectID] = string(data[pos+2 : pos+2+length])
+		pos += 2 + length
+	}
+	return id, nil
+}
+
+// ReadDeviceIDRequest 读设备标识事务 (0x2B/0x0E)
+type ReadDeviceIDRequest struct {
+	SlaveID  byte
+	ReadCode byte
+	ObjectID byte
+}
+
+// NewReadDeviceIDRequest 创建读设备标识事务
+func NewReadDeviceIDRequest(slaveID byte, readCode byte, objectID byte) *ReadDeviceIDRequest {
+	return &ReadDeviceIDRequest{SlaveID: slaveID, ReadCode: readCode, ObjectID: objectID}
+}
+
+// Encode 构造请求帧
+func (r *ReadDeviceIDRequest) Encode() []byte {
+	return BuildReadDeviceIDFrame(r.SlaveID, r.ReadCode, r.ObjectID)
+}
+
+// ParseResponse 解析响应并校验 MEI 类型与读取码
+func (r *ReadDeviceIDRequest) ParseResponse(data []byte) (*Frame, error) {
+	frame, err := validateHeader(data, r.SlaveID, FunctionEncapsulatedInterface)
+	if err != nil {
+		return frame, err
+	}
+	if len(frame.Data) < 2 || frame.Data[0] != MEIReadDeviceIdentification {
+		return nil, fmt.Errorf("MEI类型不匹配")
+	}
+	if frame.Data[1] != r.ReadCode {
+		return nil, &EchoMismatchError{Field: "读取码", Want: uint16(r.ReadCode), Got: uint16(frame.Data[1])}
+	}
+	return frame, nil
+}
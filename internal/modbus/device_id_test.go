@@ -0,0 +1,69 @@
+package modbus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// buildDeviceIDResponse 构造 0x2B/0x0E 响应帧
+func buildDeviceIDResponse(slave byte, readCode byte, more bool, next byte, objects [][2]string) []byte {
+	moreFlag := byte(0x00)
+	if more {
+		moreFlag = 0xFF
+	}
+	resp := []byte{slave, FunctionEncapsulatedInterface, MEIReadDeviceIdentification, readCode, 0x01, moreFlag, next, byte(len(objects))}
+	for _, obj := range objects {
+		resp = append(resp, obj[0][0], byte(len(obj[1])))
+		resp = append(resp, obj[1]...)
+	}
+	return binary.LittleEndian.AppendUint16(resp, CalculateCRC16(resp))
+}
+
+func TestBuildReadDeviceIDFrame(t *testing.T) {
+	frame := BuildReadDeviceIDFrame(0x01, DeviceIDBasic, ObjectVendorName)
+	want := []byte{0x01, 0x2B, 0x0E, 0x01, 0x00}
+	if !bytes.Equal(frame[:5], want) {
+		t.Fatalf("frame mismatch: got % X want % X", frame[:5], want)
+	}
+	if got := binary.LittleEndian.Uint16(frame[5:7]); got != CalculateCRC16(frame[:5]) {
+		t.Fatalf("crc mismatch")
+	}
+}
+
+func TestParseResponse_DeviceIdentification(t *testing.T) {
+	resp := buildDeviceIDResponse(0x01, DeviceIDBasic, false, 0x00, [][2]string{
+		{"\x00", "CHINT"}, {"\x01", "DDSU666"}, {"\x02", "V1.0"},
+	})
+
+	if n, ok := ExpectedResponseLength(resp[:10]); ok {
+		t.Fatalf("expected unknown length for truncated header, got %d", n)
+	}
+	if n, ok := ExpectedResponseLength(resp); !ok || n != len(resp) {
+		t.Fatalf("ExpectedResponseLength: got (%d, %v) want %d", n, ok, len(resp))
+	}
+
+	frame, err := NewReadDeviceIDRequest(0x01, DeviceIDBasic, ObjectVendorName).ParseResponse(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id, err := ParseDeviceIdentification(frame.Data)
+	if err != nil {
+		t.Fatalf("ParseDeviceIdentification failed: %v", err)
+	}
+	if id.Objects[ObjectVendorName] != "CHINT" || id.Objects[ObjectProductCode] != "DDSU666" || id.Objects[ObjectMajorMinorRevision] != "V1.0" {
+		t.Fatalf("objects mismatch: %#v", id.Objects)
+	}
+	if id.MoreFollows {
+		t.Fatalf("expected MoreFollows=false")
+	}
+}
+
+func TestParseResponse_DeviceIdentificationCRCFail(t *testing.T) {
+	resp := buildDeviceIDResponse(0x01, DeviceIDBasic, false, 0x00, [][2]string{{"\x00", "CHINT"}})
+	resp[len(resp)-3] ^= 0xFF
+
+	if _, err := ParseResponse(resp); err == nil {
+		t.Fatalf("expected CRC error")
+	}
+}
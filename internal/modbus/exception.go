@@ -0,0 +1,48 @@
+package modbus
+
+import (
+	"fmt"
+)
+
+// 异常响应码
+const (
+	ExceptionIllegalFunction    = 0x01 // 非法功能码
+	ExceptionIllegalDataAddr    = 0x02 // 非法数据地址
+	ExceptionIllegalDataValue   = 0x03 // 非法数据值
+	ExceptionSlaveDeviceFailure = 0x04 // 从站设备故障
+	ExceptionAcknowledge        = 0x05 // 确认
+	ExceptionSlaveDeviceBusy    = 0x06 // 从站设备忙
+	ExceptionMemoryParityError  = 0x08 // 存储奇偶性差错
+)
+
+// ExceptionError Modbus 异常响应，可通过 errors.As 获取异常码
+type ExceptionError struct {
+	Function byte // 原请求功能码（已去除 0x80 标志位）
+	Code     byte // 异常码
+}
+
+// Meaning 返回异常码含义
+func (e *ExceptionError) Meaning() string {
+	switch e.Code {
+	case ExceptionIllegalFunction:
+		return "非法功能码 (illegal function)"
+	case ExceptionIllegalDataAddr:
+		return "非法数据地址 (illegal data address)"
+	case ExceptionIllegalDataValue:
+		return "非法数据值 (illegal data value)"
+	case ExceptionSlaveDeviceFailure:
+		return "从站设备故障 (slave device failure)"
+	case ExceptionAcknowledge:
+		return "确认 (acknowledge)"
+	case ExceptionSlaveDeviceBusy:
+		return "从站设备忙 (slave device busy)"
+	case ExceptionMemoryParityError:
+		return "存储奇偶性差错 (memory parity error)"
+	default:
+		return "未知异常 (unknown exception)"
+	}
+}
+
+func (e *ExceptionError) Error() string {
+	return fmt.Sprintf("Modbus异常响应: 功能码 %02X, 异常码 %02X %s", e.Function, e.Code, e.Meaning())
+}
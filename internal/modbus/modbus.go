@@ -7,7 +7,15 @@ import (
 
 // 功能码
 const (
-	FunctionReadHoldingRegisters = 0x03
+	FunctionReadCoils              = 0x01
+	FunctionReadDiscreteInputs     = 0x02
+	FunctionReadHoldingRegisters   = 0x03
+	FunctionReadInputRegisters     = 0x04
+	FunctionWriteSingleCoil        = 0x05
+	FunctionWriteSingleRegister    = 0x06
+	FunctionWriteMultipleCoils     = 0x0F
+	FunctionWriteMultipleRegisters = 0x10
+	FunctionEncapsulatedInterface  = 0x2B
 )
 
 // Frame Modbus RTU 帧
@@ -20,15 +28,20 @@ type Frame struct {
 
 // BuildReadFrame 构造读寄存器帧
 func BuildReadFrame(slaveID byte, startAddr uint16, quantity uint16) []byte {
+	return BuildReadFunctionFrame(slaveID, FunctionReadHoldingRegisters, startAddr, quantity)
+}
+
+// BuildReadFunctionFrame 构造 0x01/0x02/0x03/0x04 读请求帧
+func BuildReadFunctionFrame(slaveID byte, function byte, startAddr uint16, quantity uint16) []byte {
 	frame := make([]byte, 8)
 	frame[0] = slaveID
-	frame[1] = FunctionReadHoldingRegisters
+	frame[1] = function
 	binary.BigEndian.PutUint16(frame[2:4], startAddr)
 	binary.BigEndian.PutUint16(frame[4:6], quantity)
-	
+
 	crc := CalculateCRC16(frame[:6])
 	binary.LittleEndian.PutUint16(frame[6:8], crc)
-	
+
 	return frame
 }
 
@@ -45,25 +58,40 @@ func ParseResponse(data []byte) (*Frame, error) {
 
 	// 检查是否为异常响应
 	if frame.Function&0x80 != 0 {
-		if len(data) < 5 {
-			return nil, fmt.Errorf("异常响应帧长度不足")
-		}
 		frame.Data = data[2:3] // 异常码
 		frame.CRC = binary.LittleEndian.Uint16(data[3:5])
-		return frame, fmt.Errorf("Modbus异常响应: %02X", data[2])
+		if frame.CRC != CalculateCRC16(data[:3]) {
+			return nil, fmt.Errorf("CRC校验失败")
+		}
+		return frame, &ExceptionError{Function: frame.Function &^ 0x80, Code: data[2]}
 	}
 
-	// 正常响应
-	byteCount := data[2]
-	if len(data) < int(3+byteCount+2) {
+	// 按功能码确定帧长与数据区
+	var dataStart, dataEnd int
+	switch frame.Function {
+	case FunctionWriteSingleCoil, FunctionWriteSingleRegister,
+		FunctionWriteMultipleCoils, FunctionWriteMultipleRegisters:
+		// 写响应为定长帧：地址(2) + 值/数量(2)
+		dataStart, dataEnd = 2, 6
+	case FunctionEncapsulatedInterface:
+		frameLen, ok := deviceIDResponseLength(data)
+		if !ok {
+			return nil, fmt.Errorf("响应帧数据长度不足")
+		}
+		dataStart, dataEnd = 2, frameLen-2
+	default:
+		// 读响应：字节数(1) + 数据
+		dataStart, dataEnd = 3, 3+int(data[2])
+	}
+	if len(data) < dataEnd+2 {
 		return nil, fmt.Errorf("响应帧数据长度不足")
 	}
 
-	frame.Data = data[3 : 3+byteCount]
-	frame.CRC = binary.LittleEndian.Uint16(data[3+byteCount : 3+byteCount+2])
+	frame.Data = data[dataStart:dataEnd]
+	frame.CRC = binary.LittleEndian.Uint16(data[dataEnd : dataEnd+2])
 
 	// 验证CRC
-	expectedCRC := CalculateCRC16(data[:3+byteCount])
+	expectedCRC := CalculateCRC16(data[:dataEnd])
 	if frame.CRC != expectedCRC {
 		return nil, fmt.Errorf("CRC校验失败")
 	}
@@ -74,7 +102,7 @@ func ParseResponse(data []byte) (*Frame, error) {
 // CalculateCRC16 计算CRC16校验码
 func CalculateCRC16(data []byte) uint16 {
 	crc := uint16(0xFFFF)
-	
+
 	for _, b := range data {
 		crc ^= uint16(b)
 		for i := 0; i < 8; i++ {
@@ -85,6 +113,6 @@ func CalculateCRC16(data []byte) uint16 {
 			}
 		}
 	}
-	
+
 	return crc
-}
\ No newline at end of file
+}
@@ -2,6 +2,8 @@ package modbus
 
 import (
 	"encoding/binary"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -74,6 +76,16 @@ func TestParseResponse_Exception(t *testing.T) {
 	if err == nil {
 		t.Fatalf("expected error for exception response, got nil")
 	}
+	var excErr *ExceptionError
+	if !errors.As(err, &excErr) {
+		t.Fatalf("expected ExceptionError, got %T", err)
+	}
+	if excErr.Code != excCode || excErr.Function != FunctionReadHoldingRegisters {
+		t.Fatalf("exception mismatch: got %#v", excErr)
+	}
+	if !strings.Contains(excErr.Error(), "illegal data address") {
+		t.Fatalf("expected human-readable meaning, got %q", excErr.Error())
+	}
 	if frame == nil {
 		t.Fatalf("expected non-nil frame for exception response")
 	}
@@ -83,4 +95,4 @@ func TestParseResponse_Exception(t *testing.T) {
 	if len(frame.Data) != 1 || frame.Data[0] != excCode {
 		t.Fatalf("expected exception code %02X, got %v", excCode, frame.Data)
 	}
-}
\ No newline at end of file
+}
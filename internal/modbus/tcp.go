@@ -0,0 +1,76 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Modbus TCP 常量
+const (
+	MBAPHeaderLength = 7   // 事务ID(2) + 协议ID(2) + 长度(2) + 单元ID(1)
+	MaxPDULength     = 253 // Modbus 规范规定的 PDU 最大长度
+	DefaultTCPPort   = 502
+)
+
+// MBAPHeader Modbus TCP 报文头
+type MBAPHeader struct {
+	TransactionID uint16
+	ProtocolID    uint16
+	Length        uint16 // 单元ID + PDU 的字节数
+	UnitID        byte
+}
+
+// BuildTCPFrame 将 RTU 帧转换为 Modbus TCP ADU
+// RTU 帧的从站地址映射为单元ID，去掉 CRC 后的部分作为 PDU
+func BuildTCPFrame(transactionID uint16, rtuFrame []byte) ([]byte, error) {
+	if len(rtuFrame) < 4 {
+		return nil, fmt.Errorf("RTU帧长度不足")
+	}
+	pdu := rtuFrame[1 : len(rtuFrame)-2]
+	if len(pdu) > MaxPDULength {
+		return nil, fmt.Errorf("PDU长度超限: %d", len(pdu))
+	}
+
+	adu := make([]byte, MBAPHeaderLength+len(pdu))
+	binary.BigEndian.PutUint16(adu[0:2], transactionID)
+	binary.BigEndian.PutUint16(adu[2:4], 0) // Modbus 协议固定为 0
+	binary.BigEndian.PutUint16(adu[4:6], uint16(len(pdu)+1))
+	adu[6] = rtuFrame[0]
+	copy(adu[MBAPHeaderLength:], pdu)
+
+	return adu, nil
+}
+
+// ParseMBAPHeader 解析 MBAP 报文头
+func ParseMBAPHeader(data []byte) (*MBAPHeader, error) {
+	if len(data) < MBAPHeaderLength {
+		return nil, fmt.Errorf("MBAP报文头长度不足")
+	}
+
+	header := &MBAPHeader{
+		TransactionID: binary.BigEndian.Uint16(data[0:2]),
+		ProtocolID:    binary.BigEndian.Uint16(data[2:4]),
+		Length:        binary.BigEndian.Uint16(data[4:6]),
+		UnitID:        data[6],
+	}
+	if header.ProtocolID != 0 {
+		return nil, fmt.Errorf("非Modbus协议标识: %04X", header.ProtocolID)
+	}
+	if header.Length < 2 || header.Length > MaxPDULength+1 {
+		return nil, fmt.Errorf("MBAP长度字段非法: %d", header.Length)
+	}
+
+	return header, nil
+}
+
+// TCPToRTU 将单元ID与 PDU 还原为带 CRC 的 RTU 帧，便于复用 ParseResponse
+func TCPToRTU(unitID byte, pdu []byte) []byte {
+	frame := make([]byte, 0, 1+len(pdu)+2)
+	frame = append(frame, unitID)
+	frame = append(frame, pdu...)
+
+	crc := CalculateCRC16(frame)
+	frame = binary.LittleEndian.AppendUint16(frame, crc)
+
+	return frame
+}
@@ -0,0 +1,65 @@
+package modbus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestBuildTCPFrame(t *testing.T) {
+	rtu := BuildReadFrame(0x0C, 0x2000, 2)
+
+	adu, err := BuildTCPFrame(0x1234, rtu)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []byte{0x12, 0x34, 0x00, 0x00, 0x00, 0x06, 0x0C, 0x03, 0x20, 0x00, 0x00, 0x02}
+	if !bytes.Equal(adu, want) {
+		t.Fatalf("adu mismatch: got % X want % X", adu, want)
+	}
+
+	header, err := ParseMBAPHeader(adu)
+	if err != nil {
+		t.Fatalf("ParseMBAPHeader failed: %v", err)
+	}
+	if header.TransactionID != 0x1234 || header.UnitID != 0x0C || header.Length != 6 {
+		t.Fatalf("header mismatch: %#v", header)
+	}
+}
+
+func TestBuildTCPFrame_TooShort(t *testing.T) {
+	if _, err := BuildTCPFrame(1, []byte{0x01, 0x03}); err == nil {
+		t.Fatalf("expected error for short RTU frame")
+	}
+}
+
+func TestParseMBAPHeader_Invalid(t *testing.T) {
+	// 协议ID 非 0
+	if _, err := ParseMBAPHeader([]byte{0x00, 0x01, 0x00, 0x01, 0x00, 0x06, 0x01}); err == nil {
+		t.Fatalf("expected error for non-zero protocol id")
+	}
+	// 长度字段为 0
+	if _, err := ParseMBAPHeader([]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x01}); err == nil {
+		t.Fatalf("expected error for zero length")
+	}
+	// 头部不足 7 字节
+	if _, err := ParseMBAPHeader([]byte{0x00, 0x01}); err == nil {
+		t.Fatalf("expected error for short header")
+	}
+}
+
+func TestTCPToRTU_RoundTrip(t *testing.T) {
+	pdu := []byte{FunctionReadHoldingRegisters, 0x04, 0x43, 0x5C, 0x80, 0x00}
+	rtu := TCPToRTU(0x01, pdu)
+
+	frame, err := ParseResponse(rtu)
+	if err != nil {
+		t.Fatalf("ParseResponse failed: %v", err)
+	}
+	if frame.SlaveID != 0x01 || frame.Function != FunctionReadHoldingRegisters {
+		t.Fatalf("frame header mismatch: %#v", frame)
+	}
+	if got := binary.BigEndian.Uint32(frame.Data); got != 0x435C8000 {
+		t.Fatalf("data mismatch: got %08X", got)
+	}
+}
@@ -0,0 +1,68 @@
+package modbus
+
+import (
+	"time"
+)
+
+// RTUTiming Modbus RTU 帧时序
+type RTUTiming struct {
+	CharTime         time.Duration // 单字符传输时间
+	InterCharTimeout time.Duration // t1.5：帧内字符间隔超过该值视为帧结束
+	FrameSilence     time.Duration // t3.5：两帧之间的最小静默时间
+}
+
+// NewRTUTiming 根据波特率与每字符位数（起始位+数据位+校验位+停止位）计算帧时序
+// 按 Modbus 规范，波特率高于 19200 时 t1.5、t3.5 固定为 750µs、1.75ms
+func NewRTUTiming(baudRate int, bitsPerChar int) RTUTiming {
+	if baudRate <= 0 {
+		baudRate = 9600
+	}
+	if bitsPerChar <= 0 {
+		bitsPerChar = 11
+	}
+
+	charTime := time.Duration(bitsPerChar) * time.Second / time.Duration(baudRate)
+	timing := RTUTiming{
+		CharTime:         charTime,
+		InterCharTimeout: charTime * 3 / 2,
+		FrameSilence:     charTime * 7 / 2,
+	}
+	if baudRate > 19200 {
+		timing.InterCharTimeout = 750 * time.Microsecond
+		timing.FrameSilence = 1750 * time.Microsecond
+	}
+	return timing
+}
+
+// DefaultRTUTiming 默认时序（9600bps，8N1 按 11 位保守计算）
+func DefaultRTUTiming() RTUTiming {
+	return NewRTUTiming(9600, 11)
+}
+
+// ExpectedResponseLength 根据已收到的响应头推算完整帧长度
+// 返回 false 表示数据不足以判断或功能码未知
+func ExpectedResponseLength(data []byte) (int, bool) {
+	if len(data) < 2 {
+		return 0, false
+	}
+
+	function := data[1]
+	if function&0x80 != 0 {
+		return 5, true // 从站ID + 功能码 + 异常码 + CRC
+	}
+
+	switch function {
+	case FunctionReadCoils, FunctionReadDiscreteInputs,
+		FunctionReadHoldingRegisters, FunctionReadInputRegisters:
+		if len(data) < 3 {
+			return 0, false
+		}
+		return 3 + int(data[2]) + 2, true
+	case FunctionWriteSingleCoil, FunctionWriteSingleRegister,
+		FunctionWriteMultipleCoils, FunctionWriteMultipleRegisters:
+		return 8, true
+	case FunctionEncapsulatedInterface:
+		return deviceIDResponseLength(data)
+	}
+	return 0, false
+}
@@ -0,0 +1,57 @@
+package modbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRTUTiming(t *testing.T) {
+	cases := []struct {
+		baud        int
+		bits        int
+		charTime    time.Duration
+		interChar   time.Duration
+		frameSilent time.Duration
+	}{
+		// 9600 8N1：10 位/字符
+		{9600, 10, 1041666 * time.Nanosecond, 1562499 * time.Nanosecond, 3645831 * time.Nanosecond},
+		// 2400 8E1：11 位/字符
+		{2400, 11, 4583333 * time.Nanosecond, 6874999 * time.Nanosecond, 16041665 * time.Nanosecond},
+		// 高于 19200 时使用固定值
+		{115200, 10, 86805 * time.Nanosecond, 750 * time.Microsecond, 1750 * time.Microsecond},
+	}
+	for _, tc := range cases {
+		got := NewRTUTiming(tc.baud, tc.bits)
+		if got.CharTime != tc.charTime || got.InterCharTimeout != tc.interChar || got.FrameSilence != tc.frameSilent {
+			t.Errorf("NewRTUTiming(%d, %d) = %+v", tc.baud, tc.bits, got)
+		}
+	}
+}
+
+func TestNewRTUTiming_Defaults(t *testing.T) {
+	if got, want := NewRTUTiming(0, 0), DefaultRTUTiming(); got != want {
+		t.Fatalf("expected defaults for zero config: got %+v want %+v", got, want)
+	}
+}
+
+func TestExpectedResponseLength(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		want int
+		ok   bool
+	}{
+		{"too short", []byte{0x01}, 0, false},
+		{"read header incomplete", []byte{0x01, 0x03}, 0, false},
+		{"read 2 registers", []byte{0x01, 0x03, 0x04}, 9, true},
+		{"read 16 registers", []byte{0x01, 0x03, 0x20, 0x00}, 37, true},
+		{"exception", []byte{0x01, 0x83}, 5, true},
+		{"unknown function", []byte{0x01, 0x42, 0x00}, 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := ExpectedResponseLength(tc.data)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("%s: got (%d, %v) want (%d, %v)", tc.name, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package modbus
+
+import (
+	"fmt"
+)
+
+// SlaveMismatchError 响应的从站地址与请求不一致（如其他从站的迟到响应）
+type SlaveMismatchError struct {
+	Want byte
+	Got  byte
+}
+
+func (e *SlaveMismatchError) Error() string {
+	return fmt.Sprintf("从站地址不匹配: 期望 %02X, 收到 %02X", e.Want, e.Got)
+}
+
+// FunctionMismatchError 响应的功能码与请求不一致
+type FunctionMismatchError struct {
+	Want byte
+	Got  byte
+}
+
+func (e *FunctionMismatchError) Error() string {
+	return fmt.Sprintf("功能码不匹配: 期望 %02X, 收到 %02X", e.Want, e.Got)
+}
+
+// ByteCountError 响应数据长度与请求数量不一致
+type ByteCountError struct {
+	Want int
+	Got  int
+}
+
+func (e *ByteCountError) Error() string {
+	return fmt.Sprintf("响应字节数不匹配: 期望 %d, 收到 %d", e.Want, e.Got)
+}
+
+// Transaction 请求/响应事务：Encode 构造请求帧，ParseResponse 解析并校验对应的响应
+type Transaction interface {
+	Encode() []byte
+	ParseResponse(data []byte) (*Frame, error)
+}
+
+// validateHeader 解析响应并校验从站地址与功能码（异常响应的功能码按去除 0x80 后比较）
+func validateHeader(data []byte, slaveID byte, function byte) (*Frame, error) {
+	frame, err := ParseResponse(data)
+	if frame != nil && frame.SlaveID != slaveID {
+		return nil, &SlaveMismatchError{Want: slaveID, Got: frame.SlaveID}
+	}
+	if frame != nil && frame.Function&0x7F != function {
+		return nil, &FunctionMismatchError{Want: function, Got: frame.Function}
+	}
+	return frame, err
+}
+
+// ReadRequest 读事务（0x01/0x02/0x03/0x04）
+type ReadRequest struct {
+	SlaveID   byte
+	Function  byte
+	StartAddr uint16
+	Quantity  uint16
+}
+
+// NewReadRequest 创建读保持寄存器事务
+func NewReadRequest(slaveID byte, startAddr uint16, quantity uint16) *ReadRequest {
+	return &ReadRequest{
+		SlaveID:   slaveID,
+		Function:  FunctionReadHoldingRegisters,
+		StartAddr: startAddr,
+		Quantity:  quantity,
+	}
+}
+
+// NewReadFunctionRequest 创建指定功能码的读事务
+func NewReadFunctionRequest(slaveID byte, function byte, startAddr uint16, quantity uint16) *ReadRequest {
+	return &ReadRequest{
+		SlaveID:   slaveID,
+		Function:  function,
+		StartAddr: startAddr,
+		Quantity:  quantity,
+	}
+}
+
+// Encode 构造请求帧
+func (r *ReadRequest) Encode() []byte {
+	return BuildReadFunctionFrame(r.SlaveID, r.Function, r.StartAddr, r.Quantity)
+}
+
+// expectedByteCount 期望的响应数据字节数：线圈/离散输入按位打包，寄存器每个 2 字节
+func (r *ReadRequest) expectedByteCount() int {
+	if r.Function == FunctionReadCoils || r.Function == FunctionReadDiscreteInputs {
+		return (int(r.Quantity) + 7) / 8
+	}
+	return int(r.Quantity) * 2
+}
+
+// ParseResponse 解析并校验响应：从站地址、功能码、字节数必须与请求一致
+// 异常响应返回 ParseResponse 的错误；校验失败返回对应的类型化错误
+func (r *ReadRequest) ParseResponse(data []byte) (*Frame, error) {
+	frame, err := validateHeader(data, r.SlaveID, r.Function)
+	if err != nil {
+		return frame, err
+	}
+	if want := r.expectedByteCount(); len(frame.Data) != want {
+		return nil, &ByteCountError{Want: want, Got: len(frame.Data)}
+	}
+
+	return frame, nil
+}
@@ -0,0 +1,55 @@
+package modbus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReadRequest_ParseResponse(t *testing.T) {
+	req := NewReadRequest(0x0C, 0x2000, 2)
+	data := []byte{0x43, 0x5C, 0x80, 0x00}
+
+	frame, err := req.ParseResponse(buildResponse(0x0C, FunctionReadHoldingRegisters, data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(frame.Data) != 4 {
+		t.Fatalf("data length mismatch: got %d", len(frame.Data))
+	}
+}
+
+func TestReadRequest_Mismatch(t *testing.T) {
+	req := NewReadRequest(0x0C, 0x2000, 2)
+
+	var slaveErr *SlaveMismatchError
+	_, err := req.ParseResponse(buildResponse(0x0D, FunctionReadHoldingRegisters, []byte{0, 0, 0, 0}))
+	if !errors.As(err, &slaveErr) || slaveErr.Want != 0x0C || slaveErr.Got != 0x0D {
+		t.Fatalf("expected SlaveMismatchError, got %v", err)
+	}
+
+	var funcErr *FunctionMismatchError
+	_, err = req.ParseResponse(buildResponse(0x0C, 0x04, []byte{0, 0, 0, 0}))
+	if !errors.As(err, &funcErr) || funcErr.Got != 0x04 {
+		t.Fatalf("expected FunctionMismatchError, got %v", err)
+	}
+
+	var countErr *ByteCountError
+	_, err = req.ParseResponse(buildResponse(0x0C, FunctionReadHoldingRegisters, []byte{0, 0}))
+	if !errors.As(err, &countErr) || countErr.Want != 4 || countErr.Got != 2 {
+		t.Fatalf("expected ByteCountError, got %v", err)
+	}
+}
+
+func TestReadRequest_ExceptionFromOtherSlave(t *testing.T) {
+	req := NewReadRequest(0x0C, 0x2000, 2)
+
+	// 其他从站的异常响应应按从站不匹配处理
+	resp := []byte{0x0D, FunctionReadHoldingRegisters | 0x80, 0x02}
+	crc := CalculateCRC16(resp)
+	resp = append(resp, byte(crc), byte(crc>>8))
+
+	var slaveErr *SlaveMismatchError
+	if _, err := req.ParseResponse(resp); !errors.As(err, &slaveErr) {
+		t.Fatalf("expected SlaveMismatchError, got %v", err)
+	}
+}
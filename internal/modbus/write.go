@@ -0,0 +1,127 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// 单帧寄存器数量上限
+const (
+	MaxReadRegisters  = 125 // 0x03/0x04
+	MaxWriteRegisters = 123 // 0x10
+)
+
+// BuildWriteSingleRegisterFrame 构造写单个寄存器帧 (0x06)
+func BuildWriteSingleRegisterFrame(slaveID byte, addr uint16, value uint16) []byte {
+	frame := make([]byte, 8)
+	frame[0] = slaveID
+	frame[1] = FunctionWriteSingleRegister
+	binary.BigEndian.PutUint16(frame[2:4], addr)
+	binary.BigEndian.PutUint16(frame[4:6], value)
+
+	crc := CalculateCRC16(frame[:6])
+	binary.LittleEndian.PutUint16(frame[6:8], crc)
+
+	return frame
+}
+
+// BuildWriteMultipleRegistersFrame 构造写多个寄存器帧 (0x10)
+func BuildWriteMultipleRegistersFrame(slaveID byte, startAddr uint16, values []uint16) ([]byte, error) {
+	if len(values) == 0 || len(values) > MaxWriteRegisters {
+		return nil, fmt.Errorf("写寄存器数量非法: %d", len(values))
+	}
+
+	frame := make([]byte, 7, 7+len(values)*2+2)
+	frame[0] = slaveID
+	frame[1] = FunctionWriteMultipleRegisters
+	binary.BigEndian.PutUint16(frame[2:4], startAddr)
+	binary.BigEndian.PutUint16(frame[4:6], uint16(len(values)))
+	frame[6] = byte(len(values) * 2)
+	for _, v := range values {
+		frame = binary.BigEndian.AppendUint16(frame, v)
+	}
+
+	crc := CalculateCRC16(frame)
+	frame = binary.LittleEndian.AppendUint16(frame, crc)
+
+	return frame, nil
+}
+
+// EchoMismatchError 写响应回显的地址或值/数量与请求不一致
+type EchoMismatchError struct {
+	Field string // "地址"、"值" 或 "数量"
+	Want  uint16
+	Got   uint16
+}
+
+func (e *EchoMismatchError) Error() string {
+	return fmt.Sprintf("写响应回显%s不匹配: 期望 %04X, 收到 %04X", e.Field, e.Want, e.Got)
+}
+
+// WriteSingleRequest 写单个寄存器事务 (0x06)
+type WriteSingleRequest struct {
+	SlaveID byte
+	Addr    uint16
+	Value   uint16
+}
+
+// NewWriteSingleRequest 创建写单个寄存器事务
+func NewWriteSingleRequest(slaveID byte, addr uint16, value uint16) *WriteSingleRequest {
+	return &WriteSingleRequest{SlaveID: slaveID, Addr: addr, Value: value}
+}
+
+// Encode 构造请求帧
+func (r *WriteSingleRequest) Encode() []byte {
+	return BuildWriteSingleRegisterFrame(r.SlaveID, r.Addr, r.Value)
+}
+
+// ParseResponse 解析响应并校验回显：正常响应应与请求完全一致
+func (r *WriteSingleRequest) ParseResponse(data []byte) (*Frame, error) {
+	frame, err := validateHeader(data, r.SlaveID, FunctionWriteSingleRegister)
+	if err != nil {
+		return frame, err
+	}
+	if got := binary.BigEndian.Uint16(frame.Data[0:2]); got != r.Addr {
+		return nil, &EchoMismatchError{Field: "地址", Want: r.Addr, Got: got}
+	}
+	if got := binary.BigEndian.Uint16(frame.Data[2:4]); got != r.Value {
+		return nil, &EchoMismatchError{Field: "值", Want: r.Value, Got: got}
+	}
+	return frame, nil
+}
+
+// WriteMultipleRequest 写多个寄存器事务 (0x10)
+type WriteMultipleRequest struct {
+	SlaveID   byte
+	StartAddr uint16
+	Values    []uint16
+}
+
+// NewWriteMultipleRequest 创建写多个寄存器事务
+func NewWriteMultipleRequest(slaveID byte, startAddr uint16, values []uint16) *WriteMultipleRequest {
+	return &WriteMultipleRequest{SlaveID: slaveID, StartAddr: startAddr, Values: values}
+}
+
+// Encode 构造请求帧，寄存器数量非法时返回 nil
+func (r *WriteMultipleRequest) Encode() []byte {
+	frame, err := BuildWriteMultipleRegistersFrame(r.SlaveID, r.StartAddr, r.Values)
+	if err != nil {
+		return nil
+	}
+	return frame
+}
+
+// ParseResponse 解析响应并校验回显的起始地址与数量
+func (r *WriteMultipleRequest) ParseResponse(data []byte) (*Frame, error) {
+	frame, err := validateHeader(data, r.SlaveID, FunctionWriteMultipleRegisters)
+	if err != nil {
+		return frame, err
+	}
+	if got := binary.BigEndian.Uint16(frame.Data[0:2]); got != r.StartAddr {
+		return nil, &EchoMismatchError{Field: "地址", Want: r.StartAddr, Got: got}
+	}
+	if got := binary.BigEndian.Uint16(frame.Data[2:4]); got != uint16(len(r.Values)) {
+		return nil, &EchoMismatchError{Field: "数量", Want: uint16(len(r.Values)), Got: got}
+	}
+	return frame, nil
+}
@@ -0,0 +1,100 @@
+package modbus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// buildWriteResponse 构造 0x06/0x10 定长响应帧
+func buildWriteResponse(slave byte, function byte, addr uint16, value uint16) []byte {
+	resp := []byte{slave, function, 0, 0, 0, 0}
+	binary.BigEndian.PutUint16(resp[2:4], addr)
+	binary.BigEndian.PutUint16(resp[4:6], value)
+	return binary.LittleEndian.AppendUint16(resp, CalculateCRC16(resp))
+}
+
+func TestBuildWriteSingleRegisterFrame(t *testing.T) {
+	frame := BuildWriteSingleRegisterFrame(0x0C, 0x0006, 0x0011)
+	want := []byte{0x0C, 0x06, 0x00, 0x06, 0x00, 0x11}
+	if !bytes.Equal(frame[:6], want) {
+		t.Fatalf("frame mismatch: got % X want % X", frame[:6], want)
+	}
+	if got := binary.LittleEndian.Uint16(frame[6:8]); got != CalculateCRC16(frame[:6]) {
+		t.Fatalf("crc mismatch: got %04X", got)
+	}
+}
+
+func TestBuildWriteMultipleRegistersFrame(t *testing.T) {
+	frame, err := BuildWriteMultipleRegistersFrame(0x01, 0x0006, []uint16{0x000C, 0x0003})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []byte{0x01, 0x10, 0x00, 0x06, 0x00, 0x02, 0x04, 0x00, 0x0C, 0x00, 0x03}
+	if !bytes.Equal(frame[:len(want)], want) {
+		t.Fatalf("frame mismatch: got % X want % X", frame, want)
+	}
+	if len(frame) != len(want)+2 {
+		t.Fatalf("frame length: got %d want %d", len(frame), len(want)+2)
+	}
+	if got := binary.LittleEndian.Uint16(frame[len(want):]); got != CalculateCRC16(frame[:len(want)]) {
+		t.Fatalf("crc mismatch: got %04X", got)
+	}
+
+	if _, err := BuildWriteMultipleRegistersFrame(0x01, 0, nil); err == nil {
+		t.Fatalf("expected error for empty values")
+	}
+	if _, err := BuildWriteMultipleRegistersFrame(0x01, 0, make([]uint16, MaxWriteRegisters+1)); err == nil {
+		t.Fatalf("expected error for too many values")
+	}
+}
+
+func TestWriteSingleRequest_ParseResponse(t *testing.T) {
+	req := NewWriteSingleRequest(0x0C, 0x0006, 0x0011)
+
+	if _, err := req.ParseResponse(req.Encode()); err != nil {
+		t.Fatalf("echo response rejected: %v", err)
+	}
+
+	var echoErr *EchoMismatchError
+	_, err := req.ParseResponse(buildWriteResponse(0x0C, FunctionWriteSingleRegister, 0x0006, 0x0012))
+	if !errors.As(err, &echoErr) || echoErr.Got != 0x0012 {
+		t.Fatalf("expected value EchoMismatchError, got %v", err)
+	}
+	_, err = req.ParseResponse(buildWriteResponse(0x0C, FunctionWriteSingleRegister, 0x0007, 0x0011))
+	if !errors.As(err, &echoErr) || echoErr.Got != 0x0007 {
+		t.Fatalf("expected address EchoMismatchError, got %v", err)
+	}
+}
+
+func TestWriteMultipleRequest_ParseResponse(t *testing.T) {
+	req := NewWriteMultipleRequest(0x0C, 0x0006, []uint16{0x0001, 0x0002})
+
+	if _, err := req.ParseResponse(buildWriteResponse(0x0C, FunctionWriteMultipleRegisters, 0x0006, 2)); err != nil {
+		t.Fatalf("valid response rejected: %v", err)
+	}
+
+	var echoErr *EchoMismatchError
+	_, err := req.ParseResponse(buildWriteResponse(0x0C, FunctionWriteMultipleRegisters, 0x0006, 1))
+	if !errors.As(err, &echoErr) || echoErr.Field != "数量" {
+		t.Fatalf("expected quantity EchoMismatchError, got %v", err)
+	}
+
+	var funcErr *FunctionMismatchError
+	_, err = req.ParseResponse(buildWriteResponse(0x0C, FunctionWriteSingleRegister, 0x0006, 2))
+	if !errors.As(err, &funcErr) {
+		t.Fatalf("expected FunctionMismatchError, got %v", err)
+	}
+}
+
+func TestWriteMultipleRequest_Exception(t *testing.T) {
+	req := NewWriteMultipleRequest(0x0C, 0x0006, []uint16{0x0001})
+	resp := []byte{0x0C, FunctionWriteMultipleRegisters | 0x80, ExceptionIllegalDataValue}
+	resp = binary.LittleEndian.AppendUint16(resp, CalculateCRC16(resp))
+
+	var excErr *ExceptionError
+	if _, err := req.ParseResponse(resp); !errors.As(err, &excErr) || excErr.Function != FunctionWriteMultipleRegisters {
+		t.Fatalf("expected ExceptionError for 0x10, got %v", err)
+	}
+}
@@ -28,6 +28,13 @@ func (p *DataParser) FilterElectricalData(data *registers.ElectricalData) *regis
 		PowerFactor:   data.PowerFactor,
 		Frequency:     data.Frequency,
 		ActiveEnergy:  data.ActiveEnergy,
+
+		ForwardActiveEnergy:   data.ForwardActiveEnergy,
+		ReverseActiveEnergy:   data.ReverseActiveEnergy,
+		ForwardReactiveEnergy: data.ForwardReactiveEnergy,
+		ReverseReactiveEnergy: data.ReverseReactiveEnergy,
+
+		Acquisition: data.Acquisition,
 	}
 
 	// 应用极宽松的数据过滤规则，支持所有合理数值
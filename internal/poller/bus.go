@@ -0,0 +1,112 @@
+package poller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/transport"
+)
+
+// Bus 一条通信链路（如一条 RS485 总线），挂在其上的所有从站共用
+// 事务互斥、帧间静默与时序均按链路管理，多个轮询器共享同一 Bus 时请求不会交叠
+type Bus struct {
+	conn      transport.Transport
+	mutex     sync.Mutex // 串口通信互斥锁
+	timing    modbus.RTUTiming
+	lastFrame time.Time // 最近一次总线活动时间，用于保证帧间静默
+}
+
+// NewBus 创建总线
+func NewBus(conn transport.Transport) *Bus {
+	return &Bus{conn: conn, timing: transport.TimingOf(conn)}
+}
+
+// Transact 执行一次请求/响应事务（添加串口互斥保护）
+func (b *Bus) Transact(tx modbus.Transaction, timeout time.Duration) (*modbus.Frame, error) {
+	frame, _, err := b.roundTrip(tx, timeout)
+	return frame, err
+}
+
+// roundTrip 执行一次事务并返回往返耗时（发出请求到收齐应答，不含等待总线与帧间静默的时间）
+func (b *Bus) roundTrip(tx modbus.Transaction, timeout time.Duration) (*modbus.Frame, time.Duration, error) {
+	// 串口访问互斥保护
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.conn.IsOpen() {
+		return nil, 0, fmt.Errorf("%s未打开", b.conn.Name())
+	}
+
+	// 1. 等待帧间静默并清空接收缓冲区
+	b.waitFrameSilence()
+	b.clearBuffer()
+
+	// 2. 构造请求帧
+	frame := tx.Encode()
+
+	// 3. 发送请求
+	start := time.Now()
+	_, err := b.conn.Write(frame)
+	if err != nil {
+		return nil, 0, fmt.Errorf("发送失败: %v", err)
+	}
+
+	// 4. 读取完整响应（流式解码，识别到完整帧后立即返回）
+	response, err := transport.ReadFrame(b.conn, frame, timeout)
+	b.lastFrame = time.Now()
+	rtt := b.lastFrame.Sub(start)
+	if err != nil {
+		return nil, rtt, err
+	}
+
+	// 5. 解析并校验响应（从站地址、功能码、长度/回显须与请求一致）
+	parsedResponse, err := tx.ParseResponse(response)
+	if err != nil {
+		return nil, rtt, fmt.Errorf("解析失败: %w", err)
+	}
+
+	return parsedResponse, rtt, nil
+}
+
+// SendRaw 发送任意原始帧并返回原始应答与往返耗时（调试控制台使用）
+// 与采集共用串口互斥锁；应答不做帧识别与 CRC 校验，原样返回
+func (b *Bus) SendRaw(frame []byte, timeout time.Duration) ([]byte, time.Duration, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.conn.IsOpen() {
+		return nil, 0, fmt.Errorf("%s未打开", b.conn.Name())
+	}
+
+	b.waitFrameSilence()
+	b.clearBuffer()
+
+	start := time.Now()
+	if _, err := b.conn.Write(frame); err != nil {
+		return nil, 0, fmt.Errorf("发送失败: %v", err)
+	}
+	response, last, err := transport.ReadRaw(b.conn, timeout)
+	b.lastFrame = time.Now()
+	return response, last.Sub(start), err
+}
+
+// waitFrameSilence 保证距上一次总线活动至少间隔 t3.5
+func (b *Bus) waitFrameSilence() {
+	if wait := time.Until(b.lastFrame.Add(b.timing.FrameSilence)); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// clearBuffer 清空接收缓冲区
+func (b *Bus) clearBuffer() {
+	buffer := make([]byte, 256)
+	// 最多清理5次，避免无限循环
+	for i := 0; i < 5; i++ {
+		n, err := b.conn.ReadWithTimeout(buffer, b.timing.FrameSilence)
+		if err != nil || n == 0 {
+			break // 没有更多数据
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package poller
+
+import (
+	"sort"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/registers"
+)
+
+// PlanLimits 合并读取的限制条件
+type PlanLimits struct {
+	MaxRegisters uint16 // 单次读取最多寄存器数
+	MaxGap       uint16 // 合并相邻数据点时允许一并读取的未定义寄存器数
+}
+
+// ReadPlan 一次读取事务及其覆盖的数据点
+type ReadPlan struct {
+	Function byte
+	Start    uint16
+	Quantity uint16
+	Points   []registers.RegisterDef
+}
+
+// PlanReads 将数据点合并为尽量少的读取事务
+// 按功能码与地址排序后贪心合并：与上一事务的间隔不超过 MaxGap 且合并后总长不超过 MaxRegisters 时并入，
+// 间隔中的保留地址（如 DDSU666 的 0x200C）随事务读取后丢弃。
+// 数据点按地址划分为连续段，每段在上述限制下可行则其任意子段也可行，因此贪心得到的事务数最少
+func PlanReads(defs []registers.RegisterDef, limits PlanLimits) []ReadPlan {
+	if limits.MaxRegisters == 0 || limits.MaxRegisters > modbus.MaxReadRegisters {
+		limits.MaxRegisters = modbus.MaxReadRegisters
+	}
+
+	sorted := make([]registers.RegisterDef, len(defs))
+	copy(sorted, defs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Function != sorted[j].Function {
+			return sorted[i].Function < sorted[j].Function
+		}
+		return sorted[i].Address < sorted[j].Address
+	})
+
+	var plans []ReadPlan
+	for _, d := range sorted {
+		addr := uint32(d.Address)
+		end := addr + uint32(d.Words)
+		if n := len(plans); n > 0 && plans[n-1].Function == d.Function {
+			last := &plans[n-1]
+			lastEnd := uint32(last.Start) + uint32(last.Quantity)
+			gap := int64(addr) - int64(lastEnd)
+			if gap <= int64(limits.MaxGap) && end-uint32(last.Start) <= uint32(limits.MaxRegisters) {
+				if end > lastEnd {
+					last.Quantity = uint16(end - uint32(last.Start))
+				}
+				last.Points = append(last.Points, d)
+				continue
+			}
+		}
+		plans = append(plans, ReadPlan{
+			Function: d.Function,
+			Start:    uint16(addr),
+			Quantity: uint16(d.Words),
+			Points:   []registers.RegisterDef{d},
+		})
+	}
+	return plans
+}
+
+// Extract 将事务读回的原始字节按数据点拆分写入 地址->原始字节 映射，数据不足时返回 false
+func (r ReadPlan) Extract(data []byte, regData map[uint16][]byte) bool {
+	if len(data) < int(r.Quantity)*2 {
+		return false
+	}
+	for _, d := range r.Points {
+		offset := int(uint16(d.Address)-r.Start) * 2
+		regData[uint16(d.Address)] = data[offset : offset+d.Words*2]
+	}
+	return true
+}
+
+// planProfile 为设备描述的每个分组生成读取计划
+func planProfile(profile *registers.Profile) map[string][]ReadPlan {
+	maxRegisters, maxGap := profile.ReadLimits()
+	limits := PlanLimits{MaxRegisters: uint16(maxRegisters), MaxGap: uint16(maxGap)}
+
+	plans := make(map[string][]ReadPlan)
+	for _, group := range profile.Groups() {
+		plans[group] = PlanReads(profile.Group(group), limits)
+	}
+	return plans
+}
@@ -0,0 +1,107 @@
+package poller
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/registers"
+)
+
+// span 生成 key -> 地址/寄存器数 的浮点数据点
+func span(key string, addr uint16, words int) registers.RegisterDef {
+	return registers.RegisterDef{Key: key, Function: modbus.FunctionReadHoldingRegisters, Address: registers.RegisterAddress(addr), Words: words}
+}
+
+func TestPlanReads_Gap(t *testing.T) {
+	defs := []registers.RegisterDef{
+		span("a", 0x0000, 2),
+		span("b", 0x0006, 2), // 间隔 4
+		span("c", 0x0020, 2), // 间隔 24
+	}
+
+	plans := PlanReads(defs, PlanLimits{MaxRegisters: 125, MaxGap: 4})
+	if len(plans) != 2 || plans[0].Start != 0 || plans[0].Quantity != 8 || plans[1].Start != 0x20 {
+		t.Fatalf("gap 4 plans mismatch: %+v", plans)
+	}
+	if len(plans[0].Points) != 2 || plans[0].Points[1].Key != "b" {
+		t.Fatalf("points mismatch: %+v", plans[0].Points)
+	}
+
+	// 不允许跨越空洞：每个不相邻的数据点单独读取
+	if plans := PlanReads(defs, PlanLimits{MaxRegisters: 125, MaxGap: 0}); len(plans) != 3 {
+		t.Fatalf("gap 0 plans mismatch: %+v", plans)
+	}
+	// 间隔足够大时全部合并
+	if plans := PlanReads(defs, PlanLimits{MaxRegisters: 125, MaxGap: 24}); len(plans) != 1 || plans[0].Quantity != 0x22 {
+		t.Fatalf("gap 24 plans mismatch: %+v", plans)
+	}
+	// 相邻数据点间隔为 0，不受 MaxGap 影响
+	if plans := PlanReads([]registers.RegisterDef{span("a", 0, 2), span("b", 2, 2)}, PlanLimits{MaxRegisters: 125}); len(plans) != 1 {
+		t.Fatalf("adjacent plans mismatch: %+v", plans)
+	}
+}
+
+func TestPlanReads_Limit(t *testing.T) {
+	profile, _ := registers.GetProfile(registers.DefaultProfile)
+	electrical := profile.Group(registers.GroupElectrical)
+
+	// 电参量 0x2000-0x200F 合并为一次读取（含保留地址 0x200C）
+	plans := PlanReads(electrical, PlanLimits{MaxRegisters: 125, MaxGap: registers.DefaultMaxGap})
+	if len(plans) != 1 || plans[0].Start != registers.RegVoltage || plans[0].Quantity != 16 || len(plans[0].Points) != len(electrical) {
+		t.Fatalf("electrical plans mismatch: %+v", plans)
+	}
+
+	// 超出单次读取上限时拆分，数据点不跨事务
+	plans = PlanReads(electrical, PlanLimits{MaxRegisters: 8, MaxGap: registers.DefaultMaxGap})
+	if len(plans) != 2 || plans[1].Start != registers.RegApparentPower || plans[1].Quantity != 8 {
+		t.Fatalf("split plans mismatch: %+v", plans)
+	}
+	for _, plan := range plans {
+		if plan.Quantity > 8 {
+			t.Fatalf("plan exceeds limit: %+v", plan)
+		}
+	}
+
+	// 上限为 0 或超过协议上限时按 125 处理
+	many := make([]registers.RegisterDef, 0, 100)
+	for i := 0; i < 100; i++ {
+		many = append(many, span(fmt.Sprintf("p%d", i), uint16(i*2), 2))
+	}
+	plans = PlanReads(many, PlanLimits{MaxRegisters: 1000})
+	if len(plans) != 2 || plans[0].Quantity != 124 || plans[1].Quantity != 76 {
+		t.Fatalf("protocol limit plans mismatch: %+v", plans)
+	}
+}
+
+func TestPlanReads_SplitsByFunction(t *testing.T) {
+	defs := []registers.RegisterDef{
+		{Key: "a", Function: modbus.FunctionReadInputRegisters, Address: 0x0000, Words: 2},
+		{Key: "b", Function: modbus.FunctionReadHoldingRegisters, Address: 0x0002, Words: 2},
+		{Key: "c", Function: modbus.FunctionReadInputRegisters, Address: 0x0004, Words: 2},
+	}
+	plans := PlanReads(defs, PlanLimits{MaxRegisters: 125, MaxGap: 2})
+	if len(plans) != 2 {
+		t.Fatalf("expected 2 plans, got %+v", plans)
+	}
+	if plans[0].Function != modbus.FunctionReadHoldingRegisters || plans[1].Function != modbus.FunctionReadInputRegisters || plans[1].Quantity != 6 {
+		t.Fatalf("plans mismatch: %+v", plans)
+	}
+}
+
+func TestReadPlan_Extract(t *testing.T) {
+	plan := PlanReads([]registers.RegisterDef{span("a", 0x10, 2), span("b", 0x13, 1)}, PlanLimits{MaxRegisters: 125, MaxGap: 1})[0]
+	data := []byte{0x01, 0x02, 0x03, 0x04, 0xFF, 0xFF, 0x05, 0x06}
+
+	regData := make(map[uint16][]byte)
+	if !plan.Extract(data, regData) {
+		t.Fatalf("Extract failed")
+	}
+	if !bytes.Equal(regData[0x10], data[0:4]) || !bytes.Equal(regData[0x13], data[6:8]) || len(regData) != 2 {
+		t.Fatalf("regData mismatch: %v", regData)
+	}
+	if plan.Extract(data[:6], make(map[uint16][]byte)) {
+		t.Fatalf("expected failure for short data")
+	}
+}
@@ -2,42 +2,91 @@ package poller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"DDSUViewer/internal/modbus"
 	"DDSUViewer/internal/parser"
 	"DDSUViewer/internal/registers"
-	"DDSUViewer/internal/serial"
+	"DDSUViewer/internal/transport"
 )
 
 // Poller 轮询器
 type Poller struct {
-	conn       *serial.Connection
-	slaveID    byte
-	running    bool
-	mutex      sync.RWMutex
-	ctx        context.Context
-	cancel     context.CancelFunc
-	dataChan   chan *registers.ElectricalData
-	lastData   *registers.ElectricalData
-	dataMutex  sync.RWMutex
-	commMutex  sync.Mutex // 串口通信互斥锁
-	parser     *parser.DataParser
-}
-
-// NewPoller 创建轮询器
-func NewPoller(conn *serial.Connection, slaveID byte) *Poller {
+	slaveID        byte
+	running        bool
+	mutex          sync.RWMutex
+	ctx            context.Context
+	cancel         context.CancelFunc
+	dataChan       chan *registers.ElectricalData
+	errChan        chan error // 重试后仍失败的读取错误
+	lastData       *registers.ElectricalData
+	stale          bool // 最近一次电参量读取失败，发送的是上次数据
+	threePhaseChan chan *registers.ThreePhaseData
+	lastThreePhase *registers.ThreePhaseData
+	dataMutex      sync.RWMutex
+	bus            *Bus        // 通信链路，同一总线上的多个轮询器共享
+	probing        atomic.Bool // 离线探测：读取只尝试一次，避免拖慢同一总线上的其他从站
+	readTimeout    time.Duration
+	parser         *parser.DataParser
+	profile        *registers.Profile
+	plans          map[string][]ReadPlan // 分组 -> 读取计划，随设备描述生成
+	schedule       Schedule              // 各分组轮询间隔，运行中可修改
+	lastRead       map[string]time.Time  // 分组 -> 上次定时读取时间，仅由轮询协程访问
+}
+
+var _ modbus.Executor = (*Poller)(nil)
+
+// NewPoller 创建独占链路的轮询器
+func NewPoller(conn transport.Transport, slaveID byte) *Poller {
+	return NewBusPoller(NewBus(conn), slaveID)
+}
+
+// NewBusPoller 创建共享总线的轮询器，同一总线上的从站通过 Scheduler 轮流采集
+// 默认按 DDSU666 描述读取，其他型号须在 Start 之前调用 SetProfile
+func NewBusPoller(bus *Bus, slaveID byte) *Poller {
+	profile, _ := registers.GetProfile(registers.DefaultProfile)
 	return &Poller{
-		conn:     conn,
-		slaveID:  slaveID,
-		dataChan: make(chan *registers.ElectricalData, 10),
-		parser:   parser.NewDataParser(),
+		bus:            bus,
+		slaveID:        slaveID,
+		readTimeout:    ReadTimeout,
+		dataChan:       make(chan *registers.ElectricalData, 10),
+		threePhaseChan: make(chan *registers.ThreePhaseData, 10),
+		errChan:        make(chan error, 10),
+		parser:         parser.NewDataParser(),
+		profile:        profile,
+		plans:          planProfile(profile),
+		schedule:       DefaultSchedule(),
+		lastRead:       make(map[string]time.Time),
 	}
 }
 
+// SetProfile 设置设备描述，需在 Start 之前调用
+func (p *Poller) SetProfile(profile *registers.Profile) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.profile = profile
+	p.plans = planProfile(profile)
+}
+
+// SetSchedule 设置各分组的轮询间隔，运行中调用时从下一个节拍起生效
+func (p *Poller) SetSchedule(schedule Schedule) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.schedule = schedule.Clone()
+}
+
+// Schedule 返回当前轮询计划的副本
+func (p *Poller) Schedule() Schedule {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.schedule.Clone()
+}
+
 // Start 启动轮询
 func (p *Poller) Start() error {
 	p.mutex.Lock()
@@ -50,14 +99,30 @@ func (p *Poller) Start() error {
 	p.ctx, p.cancel = context.WithCancel(context.Background())
 	p.running = true
 
-	// 启动时立即进行完整的初始化读取
-	go p.initialDataRead()
 	// 启动统一的数据轮询
 	go p.pollAllData()
 
 	return nil
 }
 
+// attach 由调度器驱动轮询：标记为运行中但不启动自身的轮询循环
+func (p *Poller) attach(parent context.Context) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.running {
+		return fmt.Errorf("从站 0x%02X 轮询已在运行", p.slaveID)
+	}
+	p.ctx, p.cancel = context.WithCancel(parent)
+	p.running = true
+	return nil
+}
+
+// SlaveID 返回从站地址
+func (p *Poller) SlaveID() byte {
+	return p.slaveID
+}
+
 // Stop 停止轮询
 func (p *Poller) Stop() {
 	p.mutex.Lock()
@@ -76,6 +141,19 @@ func (p *Poller) GetDataChannel() <-chan *registers.ElectricalData {
 	return p.dataChan
 }
 
+// GetErrorChannel 获取错误通道，读取在重试后仍失败时发送
+func (p *Poller) GetErrorChannel() <-chan error {
+	return p.errChan
+}
+
+// reportError 上报读取错误，通道满时丢弃
+func (p *Poller) reportError(err error) {
+	select {
+	case p.errChan <- err:
+	default:
+	}
+}
+
 // IsRunning 检查是否在运行
 func (p *Poller) IsRunning() bool {
 	p.mutex.RLock()
@@ -83,76 +161,77 @@ func (p *Poller) IsRunning() bool {
 	return p.running
 }
 
-// initialDataRead 启动时的初始化数据读取
-func (p *Poller) initialDataRead() {
-	log.Printf("开始初始化数据读取...")
-	
-	// 立即读取所有数据（电参量+电能）
-	data := p.readAllRegisters()
-	if data != nil {
-		// 应用数据过滤
-		filteredData := p.parser.FilterElectricalData(data)
-		if filteredData != nil {
-			log.Printf("初始化数据读取成功: 电压=%.1fV, 电能=%.3fkWh", filteredData.Voltage, filteredData.ActiveEnergy)
-			select {
-			case p.dataChan <- filteredData:
-			default:
-				// 如果通道满，直接覆盖lastData
-				p.dataMutex.Lock()
-				p.lastData = filteredData
-				p.dataMutex.Unlock()
-			}
-		}
-	} else {
-		log.Printf("初始化数据读取失败")
+// pollAllData 统一的数据轮询：启动时立即读取全部定时分组，此后按轮询计划的节拍读取
+func (p *Poller) pollAllData() {
+	p.pollCycle()
+	tickLoop(p.ctx, func() time.Duration {
+		p.mutex.RLock()
+		defer p.mutex.RUnlock()
+		return p.schedule.Tick()
+	}, func() { p.pollCycle() })
+}
+
+// pollCycle 执行一次轮询：读取电参量及已到期的低频分组
+// 读到新的有效数据时返回 true；读取失败时仍发送上次数据，但返回 false
+func (p *Poller) pollCycle() bool {
+	groups := p.dueGroups(time.Now())
+
+	if p.profile.Phases == registers.ThreePhase {
+		return p.pollThreePhase(groups)
 	}
+
+	data := p.readGroups(groups)
+	if data == nil {
+		return false
+	}
+	// 读取低频分组时输出一次详细日志
+	if len(groups) > 1 {
+		log.Printf("从站 0x%02X 数据: 电压=%.1fV, 电流=%.3fA, 功率=%.1fW, 频率=%.1fHz, 电能=%.3fkWh",
+			p.slaveID, data.Voltage, data.Current, data.ActivePower, data.Frequency, data.ActiveEnergy)
+	}
+
+	// 应用数据过滤并验证数据完整性
+	filteredData := p.parser.FilterElectricalData(data)
+	if filteredData == nil || !p.validateDataIntegrity(filteredData) {
+		return false
+	}
+	select {
+	case p.dataChan <- filteredData:
+	default:
+		// 通道满时丢弃旧数据
+	}
+	return !p.isStale()
 }
 
-// pollAllData 统一的数据轮询 (1秒周期读电参量，每10次读电能)
-func (p *Poller) pollAllData() {
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-	
-	counter := 0
-
-	for {
-		select {
-		case <-p.ctx.Done():
-			return
-		case <-ticker.C:
-			counter++
-			
-			var data *registers.ElectricalData
-			
-			// 每10秒读取完整数据（电参量+电能）
-			if counter%10 == 0 {
-				data = p.readAllRegisters()
-				// 每10秒输出一次详细日志
-				if data != nil {
-					log.Printf("第%d秒数据: 电压=%.1fV, 电流=%.3fA, 功率=%.1fW, 频率=%.1fHz, 电能=%.3fkWh",
-						counter, data.Voltage, data.Current, data.ActivePower, data.Frequency, data.ActiveEnergy)
-				}
-			} else {
-				// 其他时候只读电参量，保留电能值（静默模式）
-				data = p.readElectricalRegistersOnly()
-			}
-			
-			if data != nil {
-				// 应用数据过滤
-				filteredData := p.parser.FilterElectricalData(data)
-				if filteredData != nil {
-					// 验证数据完整性
-					if p.validateDataIntegrity(filteredData) {
-						select {
-						case p.dataChan <- filteredData:
-						default:
-							// 通道满时丢弃旧数据
-						}
-					}
-				}
-			}
+// dueGroups 返回本次需要读取的分组：电参量每次读取，其余分组在间隔到期时读取，间隔为 0 的分组不定时读取
+func (p *Poller) dueGroups(now time.Time) []string {
+	p.mutex.RLock()
+	schedule := p.schedule
+	p.mutex.RUnlock()
+
+	groups := []string{registers.GroupElectrical}
+	for _, group := range p.profile.Groups() {
+		if group == registers.GroupElectrical {
+			continue
+		}
+		interval := schedule.Interval(group)
+		if interval <= 0 {
+			continue
+		}
+		if last, ok := p.lastRead[group]; ok && now.Sub(last) < interval {
+			continue
 		}
+		p.lastRead[group] = now
+		groups = append(groups, group)
 	}
+	return groups
+}
+
+// isStale 最近一次轮询是否沿用了上次数据
+func (p *Poller) isStale() bool {
+	p.dataMutex.RLock()
+	defer p.dataMutex.RUnlock()
+	return p.stale
 }
 
 // validateDataIntegrity 验证数据完整性，适配小数值场景
@@ -161,116 +240,113 @@ func (p *Poller) validateDataIntegrity(data *registers.ElectricalData) bool {
 		log.Printf("数据验证失败: 数据为空")
 		return false
 	}
-	
+
 	// 实际的数据完整性验证：至少要有一个有效的主要参数
 	hasValidVoltage := data.Voltage > 0
 	hasValidCurrent := data.Current > 0
 	hasValidPower := data.ActivePower > 0
 	hasValidFrequency := data.Frequency > 0
 	hasValidEnergy := data.ActiveEnergy >= 0 // 电能可以为0
-	
+
 	// 至少需要电压或频率其中一个有效，表示设备通信正常
 	isValid := hasValidVoltage || hasValidFrequency
-	
+
 	// 如果有电流或功率，也认为是有效数据
 	if hasValidCurrent || hasValidPower {
 		isValid = true
 	}
-	
+
 	// 如果只有电能数据也认为是有效的（可能是待机状态）
 	if hasValidEnergy && data.ActiveEnergy > 0 {
 		isValid = true
 	}
-	
+
 	return isValid
 }
 
-// readAllRegisters 读取所有寄存器（电参量+电能）
-func (p *Poller) readAllRegisters() *registers.ElectricalData {
-	regData := make(map[uint16][]byte)
+// readStats 一次轮询中各读取事务的统计
+type readStats struct {
+	latency time.Duration // 成功事务的往返耗时之和
+	err     error         // 第一个读取错误
+}
 
-	// 1. 读取电参量寄存器 (0x2000-0x200F)
-	electricalData := p.readRegistersWithRetry(registers.RegVoltage, 16)
-	if electricalData != nil && len(electricalData) >= 32 {
-		regData[registers.RegVoltage] = electricalData[0:4]        // 0x2000
-		regData[registers.RegCurrent] = electricalData[4:8]        // 0x2002
-		regData[registers.RegActivePower] = electricalData[8:12]   // 0x2004
-		regData[registers.RegReactivePower] = electricalData[12:16] // 0x2006
-		regData[registers.RegApparentPower] = electricalData[16:20] // 0x2008
-		regData[registers.RegPowerFactor] = electricalData[20:24]   // 0x200A
-		// 跳过 0x200C (electricalData[24:28]) - 保留地址
-		regData[registers.RegFrequency] = electricalData[28:32]     // 0x200E
-	} else {
-		log.Printf("读取电参量寄存器失败")
-	}
+// acquisition 生成本次轮询的采集信息
+func (s *readStats) acquisition(quality map[string]registers.Quality) registers.Acquisition {
+	return registers.Acquisition{Timestamp: time.Now(), Latency: s.latency, Quality: quality, Err: s.err}
+}
 
-	// 2. 读取电能寄存器 (0x4000)
-	energyData := p.readRegistersWithRetry(registers.RegActiveEnergy, 2)
-	if energyData != nil && len(energyData) >= 4 {
-		regData[registers.RegActiveEnergy] = energyData
-	} else {
-		log.Printf("读取电能寄存器失败")
+// readGroup 按读取计划读取设备描述中一个分组的全部数据点，返回 地址->原始字节 映射
+// 任一读取事务失败时返回 false，已成功的事务仍写入映射
+func (p *Poller) readGroup(group string, regData map[uint16][]byte, stats *readStats) bool {
+	ok := true
+	for _, plan := range p.plans[group] {
+		data := p.readWithRetry(plan.Function, plan.Start, plan.Quantity, stats)
+		if !plan.Extract(data, regData) {
+			ok = false
+		}
 	}
+	return ok
+}
 
-	// 3. 解析数据
-	parsedData := registers.ParseElectricalData(regData)
-	
-	// 4. 更新lastData
-	p.dataMutex.Lock()
-	if parsedData != nil {
-		p.lastData = p.copyElectricalData(parsedData)
-		dataToSend := p.copyElectricalData(parsedData)
-		p.dataMutex.Unlock()
-		return dataToSend
+// ReadGroup 读取一个分组的原始寄存器数据，供端序校准等场景使用
+func (p *Poller) ReadGroup(group string) (map[uint16][]byte, error) {
+	regData := make(map[uint16][]byte)
+	if !p.readGroup(group, regData, &readStats{}) {
+		return nil, fmt.Errorf("读取 %s 分组寄存器失败", group)
 	}
-	p.dataMutex.Unlock()
-	
-	return nil
+	return regData, nil
 }
 
-// readElectricalRegistersOnly 只读取电参量寄存器，保留电能值
-func (p *Poller) readElectricalRegistersOnly() *registers.ElectricalData {
+// readGroups 读取指定分组，未读取或读取失败的其他分组沿用上次的值，结果附带各数据点质量与采集信息
+// 电参量读取失败时沿用上次数据并标记为沿用数据，尚无数据时返回 nil
+func (p *Poller) readGroups(groups []string) *registers.ElectricalData {
 	regData := make(map[uint16][]byte)
-
-	// 读取电参量寄存器 (0x2000-0x200F)
-	data := p.readRegistersWithRetry(registers.RegVoltage, 16)
-	if data != nil && len(data) >= 32 {
-		regData[registers.RegVoltage] = data[0:4]        // 0x2000
-		regData[registers.RegCurrent] = data[4:8]        // 0x2002
-		regData[registers.RegActivePower] = data[8:12]   // 0x2004
-		regData[registers.RegReactivePower] = data[12:16] // 0x2006
-		regData[registers.RegApparentPower] = data[16:20] // 0x2008
-		regData[registers.RegPowerFactor] = data[20:24]   // 0x200A
-		// 跳过 0x200C (data[24:28]) - 保留地址
-		regData[registers.RegFrequency] = data[28:32]     // 0x200E
-	} else {
-		// 如果电参量读取失败，但有之前的数据，返回之前的数据副本
-		p.dataMutex.RLock()
-		if p.lastData != nil {
-			dataToSend := p.copyElectricalData(p.lastData)
-			p.dataMutex.RUnlock()
-			return dataToSend
+	attempted := make(map[string]bool)
+	read := make(map[string]bool)
+	stats := &readStats{}
+	electricalOK := true
+	for _, group := range groups {
+		attempted[group] = true
+		if p.readGroup(group, regData, stats) {
+			read[group] = true
+			continue
+		}
+		if group == registers.GroupElectrical {
+			electricalOK = false
+		} else {
+			log.Printf("读取 %s 分组寄存器失败", group)
 		}
-		p.dataMutex.RUnlock()
-		return nil
 	}
 
-	parsedData := registers.ParseElectricalData(regData)
-	
 	p.dataMutex.Lock()
-	if parsedData != nil {
-		// 保留之前的电能值
-		if p.lastData != nil {
-			parsedData.ActiveEnergy = p.lastData.ActiveEnergy
+	defer p.dataMutex.Unlock()
+
+	p.stale = !electricalOK
+	if !electricalOK && p.lastData == nil {
+		return nil
+	}
+
+	var lastQuality map[string]registers.Quality
+	if p.lastData != nil {
+		lastQuality = p.lastData.Quality
+	}
+	quality := p.profile.AssessQuality(regData, attempted, read, lastQuality)
+
+	parsedData := p.profile.ParseElectricalData(regData)
+	// 保留未读取分组的上次值
+	if p.lastData != nil {
+		for _, d := range p.profile.Registers {
+			if read[d.Group] {
+				continue
+			}
+			if v, ok := p.lastData.Field(d.Key); ok {
+				parsedData.SetField(d.Key, v)
+			}
 		}
-		p.lastData = p.copyElectricalData(parsedData)
-		dataToSend := p.copyElectricalData(parsedData)
-		p.dataMutex.Unlock()
-		return dataToSend
 	}
-	p.dataMutex.Unlock()
-	
-	return nil
+	parsedData.Acquisition = stats.acquisition(quality)
+	p.lastData = p.copyElectricalData(parsedData)
+	return p.copyElectricalData(parsedData)
 }
 
 // copyElectricalData 创建ElectricalData的副本
@@ -284,129 +360,108 @@ func (p *Poller) copyElectricalData(src *registers.ElectricalData) *registers.El
 		PowerFactor:   src.PowerFactor,
 		Frequency:     src.Frequency,
 		ActiveEnergy:  src.ActiveEnergy,
+
+		ForwardActiveEnergy:   src.ForwardActiveEnergy,
+		ReverseActiveEnergy:   src.ReverseActiveEnergy,
+		ForwardReactiveEnergy: src.ForwardReactiveEnergy,
+		ReverseReactiveEnergy: src.ReverseReactiveEnergy,
+
+		Acquisition: src.Acquisition,
 	}
 }
 
-// readRegistersWithRetry 带重试的寄存器读取
-func (p *Poller) readRegistersWithRetry(startAddr uint16, quantity uint16) []byte {
+// readWithRetry 带重试的寄存器读取，fn 为 0x03 或 0x04；往返耗时与失败原因计入 stats
+func (p *Poller) readWithRetry(fn byte, startAddr uint16, quantity uint16, stats *readStats) []byte {
 	// 定义常量，提高可读性
 	const (
 		MaxRetries     = 3
-		BaseTimeout    = 500 * time.Millisecond
 		BaseRetryDelay = 100 * time.Millisecond
 	)
 
-	for retry := 0; retry < MaxRetries; retry++ {
-		data, err := p.readRegisters(startAddr, quantity, BaseTimeout)
+	attempts := MaxRetries
+	if p.probing.Load() {
+		attempts = 1
+	}
+
+	var lastErr error
+	for retry := 0; retry < attempts; retry++ {
+		data, rtt, err := p.readFunction(fn, startAddr, quantity, p.readTimeout)
 		if err == nil && data != nil {
 			// 验证数据长度
 			expectedLen := int(quantity) * 2
 			if len(data) >= expectedLen {
+				stats.latency += rtt
 				return data
 			}
 			// 数据长度不符合，视为失败
+			err = fmt.Errorf("数据长度不足")
+		}
+		lastErr = err
+
+		// 异常响应是从站的明确拒绝，重试不会改变结果
+		var excErr *modbus.ExceptionError
+		if errors.As(err, &excErr) {
+			break
 		}
 
 		// 指数退避重试间隔
-		if retry < MaxRetries-1 {
+		if retry < attempts-1 {
 			delay := BaseRetryDelay * time.Duration(1<<retry) // 100ms, 200ms, 400ms
 			time.Sleep(delay)
 		}
 	}
 
+	err := fmt.Errorf("读取寄存器 0x%04X 失败: %w", startAddr, lastErr)
+	if stats.err == nil {
+		stats.err = err
+	}
+	p.reportError(err)
 	return nil
 }
 
-// readRegisters 读取寄存器（添加串口互斥保护）
-func (p *Poller) readRegisters(startAddr uint16, quantity uint16, timeout time.Duration) ([]byte, error) {
-	// 串口访问互斥保护
-	p.commMutex.Lock()
-	defer p.commMutex.Unlock()
-	
-	if !p.conn.IsOpen() {
-		return nil, fmt.Errorf("串口未打开")
+// readFunction 以指定读功能码读取寄存器，同时返回往返耗时
+func (p *Poller) readFunction(fn byte, startAddr uint16, quantity uint16, timeout time.Duration) ([]byte, time.Duration, error) {
+	frame, rtt, err := p.bus.roundTrip(modbus.NewReadFunctionRequest(p.slaveID, fn, startAddr, quantity), timeout)
+	if err != nil {
+		return nil, rtt, err
 	}
+	return frame.Data, rtt, nil
+}
 
-	// 1. 清空接收缓冲区
-	p.clearBuffer()
+// ReadTimeout 轮询读取的响应超时
+const ReadTimeout = 500 * time.Millisecond
 
-	// 2. 构造读取帧
-	frame := modbus.BuildReadFrame(p.slaveID, startAddr, quantity)
+// WriteTimeout 写寄存器的响应超时
+const WriteTimeout = 1 * time.Second
 
-	// 3. 发送请求
-	_, err := p.conn.Write(frame)
-	if err != nil {
-		return nil, fmt.Errorf("发送失败: %v", err)
-	}
-
-	// 4. 等待设备处理 (Modbus RTU 3.5字符间隔，约3.5ms@9600bps，但保守等待)
-	const DeviceProcessDelay = 200 * time.Millisecond
-	time.Sleep(DeviceProcessDelay)
-
-	// 5. 计算期望的响应长度
-	expectedLen := 3 + int(quantity)*2 + 2 // 从站ID(1) + 功能码(1) + 字节数(1) + 数据(quantity*2) + CRC(2)
-	
-	// 6. 读取完整响应
-	response := p.readCompleteResponse(expectedLen)
-	if len(response) == 0 {
-		return nil, fmt.Errorf("无响应")
-	}
+// WriteRegister 写单个寄存器 (0x06)
+// 经 Bus 执行并持有总线互斥锁，写操作在两次轮询事务之间执行，不会打断正在进行的读取
+func (p *Poller) WriteRegister(addr uint16, value uint16) error {
+	_, err := p.transact(modbus.NewWriteSingleRequest(p.slaveID, addr, value), WriteTimeout)
+	return err
+}
 
-	// 7. 解析响应
-	parsedResponse, err := modbus.ParseResponse(response)
-	if err != nil {
-		return nil, fmt.Errorf("解析失败: %v", err)
+// WriteRegisters 写多个寄存器 (0x10)，同样持有总线互斥锁执行
+func (p *Poller) WriteRegisters(startAddr uint16, values []uint16) error {
+	if len(values) == 0 || len(values) > modbus.MaxWriteRegisters {
+		return fmt.Errorf("写寄存器数量非法: %d", len(values))
 	}
+	_, err := p.transact(modbus.NewWriteMultipleRequest(p.slaveID, startAddr, values), WriteTimeout)
+	return err
+}
 
-	return parsedResponse.Data, nil
+// Execute 实现 modbus.Executor，使 modbus.Client 可以复用轮询器的通信通道
+func (p *Poller) Execute(tx modbus.Transaction) (*modbus.Frame, error) {
+	return p.transact(tx, WriteTimeout)
 }
 
-// clearBuffer 清空接收缓冲区
-func (p *Poller) clearBuffer() {
-	buffer := make([]byte, 256)
-	// 最多清理5次，避免无限循环
-	for i := 0; i < 5; i++ {
-		_, err := p.conn.ReadWithTimeout(buffer, 10*time.Millisecond)
-		if err != nil {
-			break // 没有更多数据
-		}
-	}
+// transact 经总线执行一次请求/响应事务
+func (p *Poller) transact(tx modbus.Transaction, timeout time.Duration) (*modbus.Frame, error) {
+	return p.bus.Transact(tx, timeout)
 }
 
-// readCompleteResponse 读取完整响应
-func (p *Poller) readCompleteResponse(expectedLen int) []byte {
-	buffer := make([]byte, 256)
-	totalBytes := 0
-	maxAttempts := 10
-	readTimeout := 500 * time.Millisecond
-	
-	// 分段读取，确保获取完整响应
-	for attempt := 0; attempt < maxAttempts; attempt++ {
-		n, err := p.conn.ReadWithTimeout(buffer[totalBytes:], readTimeout)
-		if err != nil {
-			if totalBytes > 0 {
-				break // 已有部分数据，结束读取
-			}
-			return nil // 完全无响应
-		}
-		
-		totalBytes += n
-		
-		// 检查是否已获取足够数据
-		if totalBytes >= expectedLen {
-			break
-		}
-		
-		// 如果数据还不够，减少等待时间继续读取
-		if totalBytes > 0 {
-			time.Sleep(50 * time.Millisecond)
-		}
-	}
-	
-	// 验证最小响应长度
-	if totalBytes < 5 {
-		return nil // 响应太短，无效
-	}
-	
-	return buffer[:totalBytes]
-}
\ No newline at end of file
+// SendRaw 发送任意原始帧并返回原始应答与往返耗时（调试控制台使用）
+// 与采集共用总线互斥锁；应答不做帧识别与 CRC 校验，原样返回
+func (p *Poller) SendRaw(frame []byte, timeout time.Duration) ([]byte, time.Duration, error) {
+	return p.bus.SendRaw(frame, timeout)
+}
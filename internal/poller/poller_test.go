@@ -1,11 +1,16 @@
 package poller
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"DDSUViewer/internal/modbus"
 	"DDSUViewer/internal/registers"
 	"DDSUViewer/internal/serial"
+	"DDSUViewer/internal/simulator"
 	goserial "go.bug.st/serial"
 )
 
@@ -64,4 +69,263 @@ func TestValidateDataIntegrityAndCopy(t *testing.T) {
 	if copied.Voltage != src.Voltage || copied.ActiveEnergy != src.ActiveEnergy {
 		t.Fatalf("copy mismatch: got %#v want %#v", copied, src)
 	}
-}
\ No newline at end of file
+}
+
+// fakeTransport 内存链路：Write 时按预置响应（或 respond 的返回值）回填读缓冲
+type fakeTransport struct {
+	open     bool
+	written  [][]byte
+	response []byte
+	respond  func(request []byte) []byte
+	pending  []byte
+}
+
+func (f *fakeTransport) Open() error  { f.open = true; return nil }
+func (f *fakeTransport) Close() error { f.open = false; return nil }
+func (f *fakeTransport) IsOpen() bool { return f.open }
+func (f *fakeTransport) Name() string { return "fake" }
+
+func (f *fakeTransport) Write(data []byte) (int, error) {
+	f.written = append(f.written, append([]byte(nil), data...))
+	if f.respond != nil {
+		f.pending = append(f.pending, f.respond(data)...)
+		return len(data), nil
+	}
+	f.pending = append(f.pending, f.response...)
+	return len(data), nil
+}
+
+func (f *fakeTransport) ReadWithTimeout(buffer []byte, timeout time.Duration) (int, error) {
+	if len(f.pending) == 0 {
+		return 0, fmt.Errorf("timeout")
+	}
+	n := copy(buffer, f.pending)
+	f.pending = f.pending[n:]
+	return n, nil
+}
+
+func TestReadFunction_ThroughTransport(t *testing.T) {
+	// 0x01 03 04 43 5C 80 00 + CRC => 220.5
+	resp := []byte{0x01, 0x03, 0x04, 0x43, 0x5C, 0x80, 0x00}
+	crc := modbus.CalculateCRC16(resp)
+	resp = append(resp, byte(crc), byte(crc>>8))
+
+	ft := &fakeTransport{open: true, response: resp}
+	p := NewPoller(ft, 0x01)
+
+	data, _, err := p.readFunction(modbus.FunctionReadHoldingRegisters, registers.RegVoltage, 2, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("readFunction failed: %v", err)
+	}
+	if got := registers.ParseFloat32(data); got != 220.5 {
+		t.Fatalf("voltage: got %v want 220.5", got)
+	}
+	if len(ft.written) != 1 {
+		t.Fatalf("expected 1 request written, got %d", len(ft.written))
+	}
+	want := modbus.BuildReadFrame(0x01, registers.RegVoltage, 2)
+	if !bytes.Equal(ft.written[0], want) {
+		t.Fatalf("request mismatch: got % X want % X", ft.written[0], want)
+	}
+}
+
+func newSimulatedPoller(t *testing.T) (*Poller, *simulator.Meter) {
+	t.Helper()
+	meter := simulator.NewMeter(simulator.Config{SlaveID: 0x0C, Waveform: simulator.DefaultWaveform()})
+	tr := simulator.NewMemoryTransport(meter, 0)
+	if err := tr.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { tr.Close() })
+	return NewPoller(tr, 0x0C), meter
+}
+
+func TestReadGroup_RecoversFromFaults(t *testing.T) {
+	p, meter := newSimulatedPoller(t)
+	meter.InjectFault(simulator.FaultTimeout, 1)
+	meter.InjectFault(simulator.FaultCRC, 1)
+
+	regData, err := p.ReadGroup(registers.GroupElectrical)
+	if err != nil {
+		t.Fatalf("expected data after retries: %v", err)
+	}
+	if v := registers.ParseFloat32(regData[registers.RegVoltage]); v < 200 || v > 240 {
+		t.Fatalf("unexpected voltage %v", v)
+	}
+}
+
+func TestReadGroup_GivesUpOnPersistentFault(t *testing.T) {
+	p, meter := newSimulatedPoller(t)
+	meter.InjectFault(simulator.FaultPartialFrame, 3)
+
+	if regData, err := p.ReadGroup(registers.GroupElectrical); err == nil {
+		t.Fatalf("expected error after exhausting retries, got %v", regData)
+	}
+}
+
+func TestReadGroups_Simulated(t *testing.T) {
+	p, meter := newSimulatedPoller(t)
+
+	data := p.readGroups(p.profile.Groups())
+	if data == nil {
+		t.Fatalf("readGroups returned nil")
+	}
+	want := meter.Snapshot()
+	if data.Frequency != want.Frequency || data.PowerFactor != want.PowerFactor {
+		t.Fatalf("mismatch: got %#v want %#v", data, want)
+	}
+	if data.ActiveEnergy < 100 {
+		t.Fatalf("unexpected energy %v", data.ActiveEnergy)
+	}
+}
+
+func TestReadGroups_QualityAfterFailure(t *testing.T) {
+	p, meter := newSimulatedPoller(t)
+	p.readTimeout = 30 * time.Millisecond
+
+	first := p.readGroups(p.profile.Groups())
+	if first == nil || first.Err != nil || first.Stale() || first.Timestamp.IsZero() || first.Latency <= 0 {
+		t.Fatalf("expected fresh data with acquisition info: %+v", first)
+	}
+	if q := first.Quality[registers.KeyVoltage]; q != registers.QualityGood {
+		t.Fatalf("voltage quality = %s, want good", q)
+	}
+
+	// 电参量读取失败：沿用上次的值，但须标记为 stale 并带上错误
+	meter.InjectFault(simulator.FaultTimeout, 3)
+	data := p.readGroups([]string{registers.GroupElectrical})
+	if data == nil || data.Voltage != first.Voltage {
+		t.Fatalf("expected last voltage to be carried over: %+v", data)
+	}
+	if !data.Stale() || data.Err == nil || data.Quality[registers.KeyVoltage] != registers.QualityStale {
+		t.Fatalf("expected stale quality and error: %+v", data.Acquisition)
+	}
+	// 未到期的电能分组保留上次的质量
+	if q := data.Quality[registers.KeyActiveEnergy]; q != registers.QualityGood {
+		t.Fatalf("energy quality = %s, want good", q)
+	}
+	if !data.Timestamp.After(first.Timestamp) {
+		t.Fatalf("timestamp should be the acquisition time of this cycle")
+	}
+}
+
+func TestReadGroup_ReturnsOnCompleteFrame(t *testing.T) {
+	p, _ := newSimulatedPoller(t)
+	p.readTimeout = 500 * time.Millisecond
+
+	// 仿真电表立即应答，识别到完整帧后不应再等待固定延时或读超时
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := p.ReadGroup(registers.GroupElectrical); err != nil {
+			t.Fatalf("ReadGroup failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Fatalf("5 transactions took %v, expected frame-complete early return", elapsed)
+	}
+}
+
+func TestPollerTimingFromSerialConfig(t *testing.T) {
+	cfg := serial.Config{Port: "", BaudRate: 19200, DataBits: 8, StopBits: goserial.OneStopBit, Parity: goserial.EvenParity}
+	p := NewPoller(serial.NewConnection(cfg), 0x01)
+
+	// 19200 8E1：11 位/字符
+	want := modbus.NewRTUTiming(19200, 11)
+	if p.bus.timing != want {
+		t.Fatalf("timing mismatch: got %+v want %+v", p.bus.timing, want)
+	}
+}
+
+func TestReadFunction_RejectsOtherSlave(t *testing.T) {
+	// 其他从站 0x02 的迟到响应不能被当作本机数据
+	resp := []byte{0x02, 0x03, 0x04, 0x43, 0x5C, 0x80, 0x00}
+	crc := modbus.CalculateCRC16(resp)
+	resp = append(resp, byte(crc), byte(crc>>8))
+
+	p := NewPoller(&fakeTransport{open: true, response: resp}, 0x01)
+	_, _, err := p.readFunction(modbus.FunctionReadHoldingRegisters, registers.RegVoltage, 2, 100*time.Millisecond)
+
+	var slaveErr *modbus.SlaveMismatchError
+	if !errors.As(err, &slaveErr) {
+		t.Fatalf("expected SlaveMismatchError, got %v", err)
+	}
+}
+
+func TestReadWithRetry_ExceptionNotRetried(t *testing.T) {
+	p, _ := newSimulatedPoller(t)
+
+	start := time.Now()
+	if data := p.readWithRetry(modbus.FunctionReadHoldingRegisters, 0x1000, 2, &readStats{}); data != nil {
+		t.Fatalf("expected nil for illegal address, got % X", data)
+	}
+	// 异常响应不应触发 100ms+200ms 的退避重试
+	if elapsed := time.Since(start); elapsed > 90*time.Millisecond {
+		t.Fatalf("exception response was retried (took %v)", elapsed)
+	}
+
+	select {
+	case err := <-p.GetErrorChannel():
+		var excErr *modbus.ExceptionError
+		if !errors.As(err, &excErr) || excErr.Code != modbus.ExceptionIllegalDataAddr {
+			t.Fatalf("expected illegal data address exception, got %v", err)
+		}
+	default:
+		t.Fatalf("expected error to be reported")
+	}
+}
+
+func TestWriteRegisters_Simulated(t *testing.T) {
+	p, meter := newSimulatedPoller(t)
+
+	if err := p.WriteRegister(0x000C, 2); err != nil {
+		t.Fatalf("WriteRegister failed: %v", err)
+	}
+	if err := p.WriteRegisters(0x0005, []uint16{2, 0x0C}); err != nil {
+		t.Fatalf("WriteRegisters failed: %v", err)
+	}
+	if meter.Param(0x000C) != 2 || meter.Param(0x0005) != 2 {
+		t.Fatalf("params not written")
+	}
+
+	var excErr *modbus.ExceptionError
+	if err := p.WriteRegister(registers.RegVoltage, 1); !errors.As(err, &excErr) {
+		t.Fatalf("expected ExceptionError, got %v", err)
+	}
+	if err := p.WriteRegisters(0x0000, nil); err == nil {
+		t.Fatalf("expected error for empty write")
+	}
+}
+
+func TestWriteRegister_SerializedWithPolling(t *testing.T) {
+	p, meter := newSimulatedPoller(t)
+	if err := p.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer p.Stop()
+
+	// 轮询进行中写入，事务应互不干扰
+	for i := uint16(0); i < 5; i++ {
+		if err := p.WriteRegister(0x0003, 0x10+i); err != nil {
+			t.Fatalf("WriteRegister during polling failed: %v", err)
+		}
+	}
+	if got := meter.Param(0x0003); got != 0x14 {
+		t.Fatalf("param: got %02X want 14", got)
+	}
+}
+
+func TestExecute_ClientThroughPoller(t *testing.T) {
+	p, meter := newSimulatedPoller(t)
+	c := modbus.NewClient(p, meter.SlaveID())
+
+	if err := c.WriteSingleRegister(0x0002, 1); err != nil {
+		t.Fatalf("WriteSingleRegister failed: %v", err)
+	}
+	regs, err := c.ReadHoldingRegisters(0x0005, 2)
+	if err != nil {
+		t.Fatalf("ReadHoldingRegisters failed: %v", err)
+	}
+	if regs[0] != 2 || regs[1] != 0x0C {
+		t.Fatalf("parameter registers mismatch: %04X", regs)
+	}
+}
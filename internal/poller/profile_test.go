@@ -0,0 +1,75 @@
+package poller
+
+import (
+	"math"
+	"testing"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/registers"
+)
+
+func TestReadGroups_InputRegisterProfile(t *testing.T) {
+	profile, ok := registers.GetProfile("SDM120")
+	if !ok {
+		t.Fatalf("SDM120 profile missing")
+	}
+
+	regs := make(map[uint16]uint16)
+	putFloat32(regs, 0x0000, 229.8)  // 电压
+	putFloat32(regs, 0x0006, 2.5)    // 电流
+	putFloat32(regs, 0x000C, 560)    // 有功功率
+	putFloat32(regs, 0x001E, 0.97)   // 功率因数
+	putFloat32(regs, 0x0046, 49.98)  // 频率
+	putFloat32(regs, 0x0156, 1520.4) // 有功总电能
+	putFloat32(regs, 0x0048, 1400.2) // 正向有功电能
+	putFloat32(regs, 0x004A, 120.2)  // 反向有功电能
+	putFloat32(regs, 0x004C, 35.5)   // 正向无功电能
+	putFloat32(regs, 0x004E, 4.25)   // 反向无功电能
+
+	// 只应答 0x04：若误用 0x03 读取，所有数据点都会缺失
+	tr := &fakeTransport{open: true, respond: registerResponder(0x01, modbus.FunctionReadInputRegisters, regs)}
+	p := NewPoller(tr, 0x01)
+	p.SetProfile(profile)
+
+	data := p.readGroups(profile.Groups())
+	near := func(got float32, want float64) bool { return math.Abs(float64(got)-want) < 1e-3 }
+	if !near(data.Voltage, 229.8) || !near(data.Current, 2.5) || !near(data.ActivePower, 560) ||
+		!near(data.PowerFactor, 0.97) || !near(data.Frequency, 49.98) || !near(data.ActiveEnergy, 1520.4) {
+		t.Fatalf("SDM120 data mismatch: %+v", data)
+	}
+	if !near(data.ForwardActiveEnergy, 1400.2) || !near(data.ReverseActiveEnergy, 120.2) ||
+		!near(data.ForwardReactiveEnergy, 35.5) || !near(data.ReverseReactiveEnergy, 4.25) {
+		t.Fatalf("SDM120 directional energy mismatch: %+v", data)
+	}
+	for _, req := range tr.written {
+		if req[1] != modbus.FunctionReadInputRegisters {
+			t.Fatalf("unexpected function code 0x%02X", req[1])
+		}
+	}
+}
+
+func TestReadGroups_ScaledIntegerProfile(t *testing.T) {
+	profile, ok := registers.GetProfile("ADL200")
+	if !ok {
+		t.Fatalf("ADL200 profile missing")
+	}
+
+	regs := map[uint16]uint16{
+		0x0000: 0x0001, 0x0001: 0x86A0, // 100000 × 0.01 = 1000kWh
+		0x000B: 2205,   // 220.5V
+		0x000C: 150,    // 1.5A
+		0x000D: 0xFFF6, // -10W（反向）
+		0x0010: 985,    // 0.985
+		0x0011: 5001,   // 50.01Hz
+	}
+	tr := &fakeTransport{open: true, respond: holdingRegisters(0x01, regs)}
+	p := NewPoller(tr, 0x01)
+	p.SetProfile(profile)
+
+	data := p.readGroups(profile.Groups())
+	near := func(got float32, want float64) bool { return math.Abs(float64(got)-want) < 1e-3 }
+	if !near(data.Voltage, 220.5) || !near(data.Current, 1.5) || !near(data.ActivePower, -10) ||
+		!near(data.PowerFactor, 0.985) || !near(data.Frequency, 50.01) || !near(data.ActiveEnergy, 1000) {
+		t.Fatalf("ADL200 data mismatch: %+v", data)
+	}
+}
@@ -0,0 +1,93 @@
+package poller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"DDSUViewer/internal/registers"
+)
+
+// 轮询计划参数
+const (
+	MinPollInterval     = 100 * time.Millisecond // 分组轮询间隔下限
+	DefaultSlowInterval = 10 * time.Second       // 未在计划中列出的低频分组的轮询间隔
+)
+
+// Schedule 各分组的轮询间隔
+// 电参量分组的间隔即轮询节拍，其余分组在节拍上按各自间隔到期读取（间隔短于节拍时每拍读取）；
+// 间隔为 0 的分组不定时读取，仅在需要时经 ReadGroup 读取。未列出的分组使用 DefaultSchedule 的间隔
+type Schedule map[string]time.Duration
+
+// DefaultSchedule 默认轮询计划：电参量每秒读取，电能每 10 秒读取
+func DefaultSchedule() Schedule {
+	return Schedule{
+		registers.GroupElectrical: 1 * time.Second,
+		registers.GroupEnergy:     DefaultSlowInterval,
+	}
+}
+
+// Interval 返回分组的轮询间隔
+func (s Schedule) Interval(group string) time.Duration {
+	if d, ok := s[group]; ok {
+		return d
+	}
+	if d, ok := DefaultSchedule()[group]; ok {
+		return d
+	}
+	return DefaultSlowInterval
+}
+
+// Tick 返回轮询节拍，即电参量分组的间隔
+func (s Schedule) Tick() time.Duration {
+	if d := s.Interval(registers.GroupElectrical); d > 0 {
+		return d
+	}
+	return DefaultSchedule()[registers.GroupElectrical]
+}
+
+// Validate 检查轮询计划：电参量必须定时读取，各间隔不得低于 MinPollInterval
+func (s Schedule) Validate() error {
+	for group, d := range s {
+		if d == 0 && group != registers.GroupElectrical {
+			continue
+		}
+		if d < MinPollInterval {
+			return fmt.Errorf("%s 分组轮询间隔不能小于 %v: %v", group, MinPollInterval, d)
+		}
+	}
+	return nil
+}
+
+// Clone 返回轮询计划的副本
+func (s Schedule) Clone() Schedule {
+	c := make(Schedule, len(s))
+	for group, d := range s {
+		c[group] = d
+	}
+	return c
+}
+
+// tickLoop 按 interval() 的节拍反复执行 fn，首次执行前等待一个节拍
+// fn 耗时超过节拍时跳过错过的节拍、对齐到下一个节拍，而不是排队连续补读
+func tickLoop(ctx context.Context, interval func() time.Duration, fn func()) {
+	wait := interval()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		start := time.Now()
+		fn()
+		wait = nextTick(time.Since(start), interval())
+	}
+}
+
+// nextTick 本次耗时 elapsed 后距下一个节拍的等待时间
+func nextTick(elapsed, interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return 0
+	}
+	return interval - elapsed%interval
+}
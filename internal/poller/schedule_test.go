@@ -0,0 +1,74 @@
+package poller
+
+import (
+	"testing"
+	"time"
+
+	"DDSUViewer/internal/registers"
+)
+
+func TestScheduleValidate(t *testing.T) {
+	valid := Schedule{registers.GroupElectrical: 250 * time.Millisecond, registers.GroupEnergy: 0}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid schedule: %v", err)
+	}
+	for _, s := range []Schedule{
+		{registers.GroupElectrical: 0},
+		{registers.GroupElectrical: 50 * time.Millisecond},
+		{registers.GroupEnergy: -time.Second},
+	} {
+		if err := s.Validate(); err == nil {
+			t.Fatalf("expected error for %v", s)
+		}
+	}
+
+	s := Schedule{registers.GroupElectrical: 250 * time.Millisecond}
+	if s.Tick() != 250*time.Millisecond || s.Interval(registers.GroupEnergy) != DefaultSlowInterval || s.Interval("settings") != DefaultSlowInterval {
+		t.Fatalf("unexpected intervals: tick=%v energy=%v", s.Tick(), s.Interval(registers.GroupEnergy))
+	}
+}
+
+func TestNextTick_SkipsMissedTicks(t *testing.T) {
+	cases := []struct {
+		elapsed, want time.Duration
+	}{
+		{0, time.Second},
+		{300 * time.Millisecond, 700 * time.Millisecond},
+		{2300 * time.Millisecond, 700 * time.Millisecond}, // 错过两个节拍，对齐到第三个
+	}
+	for _, c := range cases {
+		if got := nextTick(c.elapsed, time.Second); got != c.want {
+			t.Errorf("nextTick(%v) = %v, want %v", c.elapsed, got, c.want)
+		}
+	}
+}
+
+func TestDueGroups(t *testing.T) {
+	profile, ok := registers.GetProfile("SDM120")
+	if !ok {
+		t.Fatalf("SDM120 profile not found")
+	}
+	p := NewPoller(nil, 0x0C)
+	p.SetProfile(profile)
+	p.SetSchedule(Schedule{registers.GroupElectrical: 250 * time.Millisecond, registers.GroupEnergy: time.Minute})
+
+	now := time.Now()
+	if got := p.dueGroups(now); len(got) != 2 {
+		t.Fatalf("first cycle should read all scheduled groups: %v", got)
+	}
+	if got := p.dueGroups(now.Add(30 * time.Second)); len(got) != 1 || got[0] != registers.GroupElectrical {
+		t.Fatalf("energy should not be due yet: %v", got)
+	}
+
+	// 运行中修改计划，下一次轮询即按新间隔判断
+	p.SetSchedule(Schedule{registers.GroupElectrical: 250 * time.Millisecond, registers.GroupEnergy: 10 * time.Second})
+	if got := p.dueGroups(now.Add(30 * time.Second)); len(got) != 2 {
+		t.Fatalf("energy should be due after shortening its interval: %v", got)
+	}
+
+	// 间隔为 0 的分组只在需要时读取
+	p.SetSchedule(Schedule{registers.GroupElectrical: 250 * time.Millisecond, registers.GroupEnergy: 0})
+	if got := p.dueGroups(now.Add(time.Hour)); len(got) != 1 {
+		t.Fatalf("on-demand group should not be polled: %v", got)
+	}
+}
@@ -0,0 +1,285 @@
+package poller
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// 离线退避参数：连续失败达到离线阈值（默认 DefaultOfflineAfter，可由 SetOfflineAfter 修改）的从站视为离线，
+// 此后按 minBackoff 起指数增长（上限 maxBackoff）的间隔探测，每次探测只尝试一次
+const (
+	DefaultOfflineAfter = 3
+	minBackoff          = 2 * time.Second
+	maxBackoff          = 60 * time.Second
+)
+
+// DeviceHealth 从站在总线上的在线状态
+type DeviceHealth struct {
+	SlaveID   byte
+	Online    bool
+	Successes int       // 连续成功次数
+	Failures  int       // 连续失败次数
+	LastSeen  time.Time // 最近一次读到有效数据的时间
+	NextPoll  time.Time // 离线退避期间的下一次探测时间
+}
+
+// scheduledDevice 调度器中的一个从站
+type scheduledDevice struct {
+	poller *Poller
+	health DeviceHealth
+}
+
+// Scheduler 在一条总线上轮流采集多个从站
+// 每轮按添加顺序依次轮询各从站，轮与轮之间按轮询计划的节拍对齐，总线跟不上时跳过节拍；
+// 离线从站按退避间隔探测，不会拖慢在线从站的采集
+type Scheduler struct {
+	bus          *Bus
+	schedule     Schedule
+	offlineAfter int // 连续失败达到该次数视为离线并开始退避
+	mutex        sync.RWMutex
+	devices      []*scheduledDevice
+	running      bool
+	cancel       context.CancelFunc
+	done         chan struct{}     // Stop 时关闭，通知数据监听方退出
+	healthCh     chan DeviceHealth // 每次轮询后的从站状态
+}
+
+// NewScheduler 创建总线调度器，schedule 为总线上全部从站共用的轮询计划
+func NewScheduler(bus *Bus, schedule Schedule) *Scheduler {
+	return &Scheduler{
+		bus:          bus,
+		schedule:     schedule.Clone(),
+		offlineAfter: DefaultOfflineAfter,
+		done:         make(chan struct{}),
+		healthCh:     make(chan DeviceHealth, 10),
+	}
+}
+
+// SetOfflineAfter 设置离线阈值，n <= 0 时使用 DefaultOfflineAfter；运行中调用时从下一次轮询起生效
+func (s *Scheduler) SetOfflineAfter(n int) {
+	if n <= 0 {
+		n = DefaultOfflineAfter
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.offlineAfter = n
+}
+
+// OfflineAfter 返回当前离线阈值
+func (s *Scheduler) OfflineAfter() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.offlineAfter
+}
+
+// SetSchedule 修改轮询计划，运行中调用时从下一个节拍起生效，无需重启
+func (s *Scheduler) SetSchedule(schedule Schedule) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.schedule = schedule.Clone()
+	for _, d := range s.devices {
+		d.poller.SetSchedule(schedule)
+	}
+}
+
+// Schedule 返回当前轮询计划的副本
+func (s *Scheduler) Schedule() Schedule {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.schedule.Clone()
+}
+
+// Add 添加一个从站的轮询器，须在 Start 之前调用
+func (s *Scheduler) Add(p *Poller) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.running {
+		return fmt.Errorf("调度器运行中，不能添加从站")
+	}
+	if p.bus != s.bus {
+		return fmt.Errorf("从站 0x%02X 不在本总线上", p.slaveID)
+	}
+	for _, d := range s.devices {
+		if d.poller.slaveID == p.slaveID {
+			return fmt.Errorf("从站地址重复: 0x%02X", p.slaveID)
+		}
+	}
+	p.SetSchedule(s.schedule)
+	s.devices = append(s.devices, &scheduledDevice{poller: p, health: DeviceHealth{SlaveID: p.slaveID}})
+	return nil
+}
+
+// Pollers 返回全部从站的轮询器，按添加顺序
+func (s *Scheduler) Pollers() []*Poller {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	pollers := make([]*Poller, len(s.devices))
+	for i, d := range s.devices {
+		pollers[i] = d.poller
+	}
+	return pollers
+}
+
+// Start 启动调度
+func (s *Scheduler) Start() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.running {
+		return fmt.Errorf("调度器已在运行")
+	}
+	if len(s.devices) == 0 {
+		return fmt.Errorf("总线上没有从站")
+	}
+	select {
+	case <-s.done:
+		s.done = make(chan struct{}) // 停止后再次启动
+	default:
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for _, d := range s.devices {
+		if err := d.poller.attach(ctx); err != nil {
+			cancel()
+			return err
+		}
+	}
+	s.cancel = cancel
+	s.running = true
+
+	go s.run(ctx)
+	return nil
+}
+
+// Stop 停止调度
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.running {
+		return
+	}
+	s.cancel()
+	for _, d := range s.devices {
+		d.poller.Stop()
+	}
+	s.running = false
+	close(s.done)
+}
+
+// Done 返回在调度停止时关闭的通道
+// 轮询器与调度器的数据通道不会关闭（停止时可能仍有进行中的读取），监听方须同时等待该通道以便退出
+func (s *Scheduler) Done() <-chan struct{} {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.done
+}
+
+// IsRunning 检查是否在运行
+func (s *Scheduler) IsRunning() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.running
+}
+
+// Health 返回各从站的在线状态，按添加顺序
+func (s *Scheduler) Health() []DeviceHealth {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	health := make([]DeviceHealth, len(s.devices))
+	for i, d := range s.devices {
+		health[i] = d.health
+	}
+	return health
+}
+
+// GetHealthChannel 获取从站状态通道，每次轮询（含离线探测）后发送该从站的最新状态，通道满时丢弃
+// 状态中的连续成功/失败次数为累计值，丢弃个别消息不影响接收方按阈值判断
+func (s *Scheduler) GetHealthChannel() <-chan DeviceHealth {
+	return s.healthCh
+}
+
+// run 调度主循环：启动后立即轮询一轮，此后按节拍轮询
+func (s *Scheduler) run(ctx context.Context) {
+	s.mutex.RLock()
+	devices := s.devices
+	s.mutex.RUnlock()
+
+	round := func() {
+		for _, d := range devices {
+			if ctx.Err() != nil {
+				return
+			}
+			s.poll(d, len(devices) > 1)
+		}
+	}
+	round()
+	tickLoop(ctx, func() time.Duration {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+		return s.schedule.Tick()
+	}, round)
+}
+
+// poll 轮询一个从站并更新其在线状态；shared 为 true 时对离线从站退避
+// 只有一个从站时不存在饿死问题，离线后仍每轮完整重试，尽快发现恢复
+func (s *Scheduler) poll(d *scheduledDevice, shared bool) {
+	s.mutex.RLock()
+	health := d.health
+	threshold := s.offlineAfter
+	s.mutex.RUnlock()
+
+	offline := shared && health.Failures >= threshold
+	if offline && time.Now().Before(health.NextPoll) {
+		return
+	}
+
+	d.poller.probing.Store(offline)
+	ok := d.poller.pollCycle()
+
+	now := time.Now()
+	if ok {
+		if !health.Online && health.Failures >= threshold {
+			log.Printf("从站 0x%02X 恢复在线", health.SlaveID)
+		}
+		health.Online = true
+		health.Successes++
+		health.Failures = 0
+		health.LastSeen = now
+		health.NextPoll = time.Time{}
+	} else {
+		health.Successes = 0
+		health.Failures++
+		if health.Failures >= threshold {
+			if health.Failures == threshold {
+				log.Printf("从站 0x%02X 连续 %d 次无有效数据，视为离线", health.SlaveID, health.Failures)
+			}
+			health.Online = false
+			health.NextPoll = now.Add(backoff(health.Failures - threshold))
+		}
+	}
+
+	s.mutex.Lock()
+	d.health = health
+	s.mutex.Unlock()
+
+	select {
+	case s.healthCh <- health:
+	default:
+	}
+}
+
+// backoff 离线后第 n 次探测失败的退避间隔
+func backoff(n int) time.Duration {
+	if n > 5 {
+		return maxBackoff
+	}
+	if d := minBackoff << n; d < maxBackoff {
+		return d
+	}
+	return maxBackoff
+}
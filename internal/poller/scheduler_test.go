@@ -0,0 +1,135 @@
+package poller
+
+import (
+	"testing"
+	"time"
+
+	"DDSUViewer/internal/registers"
+	"DDSUViewer/internal/simulator"
+)
+
+// newSimulatedBus 创建挂有多台仿真电表的总线及其调度器，absent 为总线上不存在的从站地址
+func newSimulatedBus(t *testing.T, present []byte, absent []byte) *Scheduler {
+	t.Helper()
+	var meters []*simulator.Meter
+	for _, id := range present {
+		meters = append(meters, simulator.NewMeter(simulator.Config{SlaveID: id, Waveform: simulator.DefaultWaveform()}))
+	}
+	tr := simulator.NewBusTransport(meters, 0)
+	if err := tr.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { tr.Close() })
+
+	bus := NewBus(tr)
+	s := NewScheduler(bus, Schedule{registers.GroupElectrical: 20 * time.Millisecond})
+	for _, id := range append(append([]byte(nil), present...), absent...) {
+		p := NewBusPoller(bus, id)
+		p.readTimeout = 30 * time.Millisecond
+		if err := s.Add(p); err != nil {
+			t.Fatalf("Add 0x%02X failed: %v", id, err)
+		}
+	}
+	return s
+}
+
+func TestScheduler_PollsAllSlaves(t *testing.T) {
+	s := newSimulatedBus(t, []byte{0x01, 0x02, 0x03}, nil)
+	if err := s.Add(NewBusPoller(s.bus, 0x02)); err == nil {
+		t.Fatalf("expected error for duplicate slave")
+	}
+	if err := s.Add(NewPoller(s.bus.conn, 0x04)); err == nil {
+		t.Fatalf("expected error for poller on another bus")
+	}
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer s.Stop()
+
+	for _, p := range s.Pollers() {
+		if !p.IsRunning() {
+			t.Fatalf("poller 0x%02X should be running", p.SlaveID())
+		}
+		select {
+		case data := <-p.GetDataChannel():
+			if data.Voltage < 200 || data.Voltage > 240 {
+				t.Fatalf("slave 0x%02X voltage %v", p.SlaveID(), data.Voltage)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no data from slave 0x%02X", p.SlaveID())
+		}
+	}
+	for _, h := range s.Health() {
+		if !h.Online || h.Failures != 0 {
+			t.Fatalf("unexpected health: %+v", h)
+		}
+	}
+
+	select {
+	case <-s.Done():
+		t.Fatalf("done channel closed while running")
+	default:
+	}
+	s.Stop()
+	for _, p := range s.Pollers() {
+		if p.IsRunning() {
+			t.Fatalf("poller 0x%02X should be stopped", p.SlaveID())
+		}
+	}
+	select {
+	case <-s.Done():
+	default:
+		t.Fatalf("done channel not closed after stop")
+	}
+}
+
+func TestScheduler_BacksOffOfflineSlave(t *testing.T) {
+	s := newSimulatedBus(t, []byte{0x01}, []byte{0x09})
+	s.SetOfflineAfter(2)
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer s.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		h := s.Health()[1]
+		if h.Failures >= 2 {
+			if h.Online || h.NextPoll.IsZero() {
+				t.Fatalf("offline slave health: %+v", h)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("slave 0x09 never marked offline: %+v", h)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 退避期间离线从站不再被轮询，在线从站按轮询间隔持续出数
+	healthy := s.Pollers()[0]
+	for len(healthy.GetDataChannel()) > 0 {
+		<-healthy.GetDataChannel()
+	}
+	failures := s.Health()[1].Failures
+	received := 0
+	timeout := time.After(500 * time.Millisecond)
+	for received < 5 {
+		select {
+		case <-healthy.GetDataChannel():
+			received++
+		case <-timeout:
+			t.Fatalf("healthy slave starved: %d updates in 500ms", received)
+		}
+	}
+	if h := s.Health()[1]; h.Failures != failures {
+		t.Fatalf("offline slave polled during backoff: %+v", h)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	if backoff(0) != minBackoff || backoff(1) != 2*minBackoff || backoff(100) != maxBackoff {
+		t.Fatalf("backoff sequence mismatch: %v %v %v", backoff(0), backoff(1), backoff(100))
+	}
+}
@@ -0,0 +1,92 @@
+package poller
+
+import (
+	"log"
+
+	"DDSUViewer/internal/registers"
+)
+
+// GetThreePhaseChannel 获取三相数据通道（设备描述为三相时使用）
+func (p *Poller) GetThreePhaseChannel() <-chan *registers.ThreePhaseData {
+	return p.threePhaseChan
+}
+
+// pollThreePhase 读取一次三相数据并发送，groups 为本次读取的分组
+// 读到新的有效数据时返回 true
+func (p *Poller) pollThreePhase(groups []string) bool {
+	data := p.readThreePhase(groups)
+	if data == nil || !validThreePhase(data) {
+		return false
+	}
+	select {
+	case p.threePhaseChan <- data:
+	default:
+		// 通道满时丢弃
+	}
+	return !p.isStale()
+}
+
+// readThreePhase 读取指定分组的三相电参量，结果附带各数据点质量与采集信息
+// 未读取的分组沿用上次的值，电参量读取失败时沿用上次数据，尚无数据时返回 nil
+func (p *Poller) readThreePhase(groups []string) *registers.ThreePhaseData {
+	regData := make(map[uint16][]byte)
+	attempted := make(map[string]bool)
+	read := make(map[string]bool)
+	stats := &readStats{}
+	electricalOK := true
+	for _, group := range groups {
+		attempted[group] = true
+		if p.readGroup(group, regData, stats) {
+			read[group] = true
+			continue
+		}
+		log.Printf("读取 %s 分组寄存器失败", group)
+		if group == registers.GroupElectrical {
+			electricalOK = false
+		}
+	}
+
+	p.dataMutex.Lock()
+	defer p.dataMutex.Unlock()
+
+	p.stale = !electricalOK
+	if !electricalOK && p.lastThreePhase == nil {
+		return nil
+	}
+
+	var lastQuality map[string]registers.Quality
+	if p.lastThreePhase != nil {
+		lastQuality = p.lastThreePhase.Quality
+	}
+	quality := p.profile.AssessQuality(regData, attempted, read, lastQuality)
+
+	data := p.profile.ParseThreePhaseData(regData)
+	if !electricalOK {
+		// 以上次数据为基础（含推算的视在功率），只更新本次读取成功的分组
+		last := *p.lastThreePhase
+		for _, d := range p.profile.Registers {
+			if v, ok := data.Field(d.Key); ok && read[d.Group] {
+				last.SetField(d.Key, v)
+			}
+		}
+		data = &last
+	} else if p.lastThreePhase != nil {
+		for _, d := range p.profile.Registers {
+			if read[d.Group] {
+				continue
+			}
+			if v, ok := p.lastThreePhase.Field(d.Key); ok {
+				data.SetField(d.Key, v)
+			}
+		}
+	}
+	data.Acquisition = stats.acquisition(quality)
+	p.lastThreePhase = data
+	result := *data
+	return &result
+}
+
+// validThreePhase 至少一相电压或频率有效时认为通信正常
+func validThreePhase(data *registers.ThreePhaseData) bool {
+	return data.PhaseA.Voltage > 0 || data.PhaseB.Voltage > 0 || data.PhaseC.Voltage > 0 || data.Frequency > 0
+}
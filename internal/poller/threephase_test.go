@@ -0,0 +1,95 @@
+package poller
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/registers"
+)
+
+// holdingRegisters 按寄存器表应答 0x03 读请求，未定义的地址返回 0
+func holdingRegisters(slave byte, regs map[uint16]uint16) func(request []byte) []byte {
+	return registerResponder(slave, modbus.FunctionReadHoldingRegisters, regs)
+}
+
+// registerResponder 按寄存器表应答指定功能码（0x03/0x04）的读请求，其他功能码不应答
+func registerResponder(slave byte, fn byte, regs map[uint16]uint16) func(request []byte) []byte {
+	return func(request []byte) []byte {
+		if request[0] != slave || request[1] != fn {
+			return nil
+		}
+		start := binary.BigEndian.Uint16(request[2:4])
+		quantity := binary.BigEndian.Uint16(request[4:6])
+		resp := []byte{slave, request[1], byte(quantity * 2)}
+		for i := uint16(0); i < quantity; i++ {
+			resp = binary.BigEndian.AppendUint16(resp, regs[start+i])
+		}
+		return binary.LittleEndian.AppendUint16(resp, modbus.CalculateCRC16(resp))
+	}
+}
+
+// putFloat32 将 float32 以 ABCD 字节序写入寄存器表
+func putFloat32(regs map[uint16]uint16, addr uint16, v float32) {
+	bits := math.Float32bits(v)
+	regs[addr] = uint16(bits >> 16)
+	regs[addr+1] = uint16(bits)
+}
+
+func TestReadThreePhase_DTSU666(t *testing.T) {
+	profile, ok := registers.GetProfile("DTSU666")
+	if !ok || profile.Phases != registers.ThreePhase {
+		t.Fatalf("DTSU666 profile missing or not three-phase")
+	}
+
+	regs := make(map[uint16]uint16)
+	putFloat32(regs, 0x2000, 3810)  // Uab 381.0V
+	putFloat32(regs, 0x2006, 2205)  // Ua 220.5V
+	putFloat32(regs, 0x2008, 2210)  // Ub 221.0V
+	putFloat32(regs, 0x200C, 1500)  // Ia 1.5A
+	putFloat32(regs, 0x2012, 9900)  // Pt 990W
+	putFloat32(regs, 0x2014, 3300)  // Pa 330W
+	putFloat32(regs, 0x201C, 1000)  // Qa 100var
+	putFloat32(regs, 0x202C, 957)   // PFa 0.957
+	putFloat32(regs, 0x2044, 5000)  // 50.00Hz
+	putFloat32(regs, 0x401E, 123.5) // 正向有功 kWh
+	putFloat32(regs, 0x4028, 0.25)  // 反向有功 kWh
+
+	tr := &fakeTransport{open: true, respond: holdingRegisters(0x01, regs)}
+	p := NewPoller(tr, 0x01)
+	p.SetProfile(profile)
+
+	data := p.readThreePhase(p.profile.Groups())
+	if data == nil {
+		t.Fatalf("readThreePhase returned nil")
+	}
+	near := func(got float32, want float64) bool { return math.Abs(float64(got)-want) < 1e-3 }
+	if !near(data.VoltageAB, 381) || !near(data.PhaseA.Voltage, 220.5) || !near(data.PhaseB.Voltage, 221) {
+		t.Fatalf("voltages mismatch: %+v", data)
+	}
+	if !near(data.PhaseA.Current, 1.5) || !near(data.TotalActivePower, 990) || !near(data.PhaseA.PowerFactor, 0.957) {
+		t.Fatalf("current/power mismatch: %+v", data)
+	}
+	if !near(data.PhaseA.ApparentPower, math.Hypot(330, 100)) {
+		t.Fatalf("derived apparent power: got %v", data.PhaseA.ApparentPower)
+	}
+	if !near(data.Frequency, 50) || !near(data.ImportEnergy, 123.5) || !near(data.ExportEnergy, 0.25) {
+		t.Fatalf("frequency/energy mismatch: %+v", data)
+	}
+
+	// 只读电参量时电能沿用上次的值
+	putFloat32(regs, 0x401E, 200)
+	putFloat32(regs, 0x2006, 2300)
+	data = p.readThreePhase([]string{registers.GroupElectrical})
+	if !near(data.PhaseA.Voltage, 230) || !near(data.ImportEnergy, 123.5) {
+		t.Fatalf("partial read mismatch: Ua=%v import=%v", data.PhaseA.Voltage, data.ImportEnergy)
+	}
+
+	// 电参量读取失败时返回上次数据
+	tr.respond = func([]byte) []byte { return nil }
+	data = p.readThreePhase([]string{registers.GroupElectrical})
+	if data == nil || !near(data.PhaseA.Voltage, 230) {
+		t.Fatalf("expected last data on failure, got %+v", data)
+	}
+}
@@ -5,49 +5,45 @@ import (
 	"time"
 
 	"DDSUViewer/internal/modbus"
-	"DDSUViewer/internal/serial"
+	"DDSUViewer/internal/transport"
 )
 
 // Detector 协议探测器
 type Detector struct {
-	conn *serial.Connection
+	conn transport.Transport
 }
 
 // NewDetector 创建协议探测器
-func NewDetector(conn *serial.Connection) *Detector {
+func NewDetector(conn transport.Transport) *Detector {
 	return &Detector{conn: conn}
 }
 
 // TestModbusConnection 测试Modbus连接
 func (d *Detector) TestModbusConnection(slaveID byte) error {
 	if !d.conn.IsOpen() {
-		return fmt.Errorf("串口未打开")
+		return fmt.Errorf("%s未打开", d.conn.Name())
 	}
 
 	// 构造测试帧：按文档示例 0C 03 20 00 00 02
-	testFrame := modbus.BuildReadFrame(slaveID, 0x2000, 2)
-	
+	request := modbus.NewReadRequest(slaveID, 0x2000, 2)
+	testFrame := request.Encode()
+
 	// 发送测试帧
 	_, err := d.conn.Write(testFrame)
 	if err != nil {
 		return fmt.Errorf("发送测试帧失败: %v", err)
 	}
 
-	// 读取响应
-	buffer := make([]byte, 256)
-	n, err := d.conn.ReadWithTimeout(buffer, 200*time.Millisecond)
+	// 读取响应（流式解码，自动跳过噪声与回显）
+	response, err := transport.ReadFrame(d.conn, testFrame, 200*time.Millisecond)
 	if err != nil {
-		return fmt.Errorf("读取响应超时: %v", err)
-	}
-
-	if n == 0 {
-		return fmt.Errorf("未收到响应")
+		return fmt.Errorf("读取响应失败: %v", err)
 	}
 
 	// 解析响应
-	_, err = modbus.ParseResponse(buffer[:n])
+	_, err = request.ParseResponse(response)
 	if err != nil {
-		return fmt.Errorf("响应解析失败: %v", err)
+		return fmt.Errorf("响应解析失败: %w", err)
 	}
 
 	return nil
@@ -62,4 +58,4 @@ func (d *Detector) DetectProtocol(slaveID byte) (string, error) {
 	}
 
 	return "Unknown", fmt.Errorf("未检测到支持的协议: %v", err)
-}
\ No newline at end of file
+}
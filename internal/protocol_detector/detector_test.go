@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"DDSUViewer/internal/serial"
+	"DDSUViewer/internal/simulator"
 	goserial "go.bug.st/serial"
 )
 
@@ -19,4 +20,22 @@ func TestDetectProtocol_WithClosedConn(t *testing.T) {
 	if proto != "Unknown" {
 		t.Fatalf("expected Unknown, got %s", proto)
 	}
-}
\ No newline at end of file
+}
+
+func TestDetectProtocol_Simulator(t *testing.T) {
+	meter := simulator.NewMeter(simulator.Config{SlaveID: 0x0C, Waveform: simulator.DefaultWaveform()})
+	tr := simulator.NewMemoryTransport(meter, 0)
+	if err := tr.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer tr.Close()
+
+	d := NewDetector(tr)
+	proto, err := d.DetectProtocol(0x0C)
+	if err != nil {
+		t.Fatalf("DetectProtocol failed: %v", err)
+	}
+	if proto != "Modbus RTU" {
+		t.Fatalf("expected Modbus RTU, got %s", proto)
+	}
+}
@@ -0,0 +1,112 @@
+package registers
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ByteOrders 全部受支持的字节序，按优先级排列（得分相同时靠前者优先）
+var ByteOrders = []string{ByteOrderABCD, ByteOrderCDAB, ByteOrderBADC, ByteOrderDCBA}
+
+// plausibleRange 数据点的合理取值范围与权重
+type plausibleRange struct {
+	min, max float64
+	weight   int
+}
+
+// plausibleRanges 端序校准时用于判断数值是否合理的范围
+// 电压、频率最具区分度，权重较高；其余数据点只用于排除明显错误的解析
+var plausibleRanges = map[string]plausibleRange{
+	KeyVoltage:       {100, 260, 3},
+	KeyFrequency:     {45, 65, 3},
+	KeyPowerFactor:   {-1, 1, 1},
+	KeyCurrent:       {-100, 100, 1},
+	KeyActivePower:   {-30000, 30000, 1},
+	KeyReactivePower: {-30000, 30000, 1},
+	KeyApparentPower: {-30000, 30000, 1},
+	KeyActiveEnergy:  {0, 1e9, 1},
+
+	KeyForwardActiveEnergy:   {0, 1e9, 1},
+	KeyReverseActiveEnergy:   {0, 1e9, 1},
+	KeyForwardReactiveEnergy: {0, 1e9, 1},
+	KeyReverseReactiveEnergy: {0, 1e9, 1},
+
+	KeyVoltageA:           {100, 260, 3},
+	KeyVoltageB:           {100, 260, 3},
+	KeyVoltageC:           {100, 260, 3},
+	KeyVoltageAB:          {170, 460, 2},
+	KeyVoltageBC:          {170, 460, 2},
+	KeyVoltageCA:          {170, 460, 2},
+	KeyTotalPowerFactor:   {-1, 1, 1},
+	KeyPowerFactorA:       {-1, 1, 1},
+	KeyPowerFactorB:       {-1, 1, 1},
+	KeyPowerFactorC:       {-1, 1, 1},
+	KeyTotalActivePower:   {-100000, 100000, 1},
+	KeyTotalReactivePower: {-100000, 100000, 1},
+}
+
+// ByteOrderCandidate 一种字节序下的解析结果与可信度得分
+type ByteOrderCandidate struct {
+	ByteOrder string
+	Score     int
+	Values    map[string]float64 // 数据点键 -> 工程量
+}
+
+// WithByteOrder 返回所有数据点改用指定字节序的描述副本
+func (p *Profile) WithByteOrder(order string) (*Profile, error) {
+	if !ValidByteOrder(order) {
+		return nil, fmt.Errorf("不支持的字节序: %s", order)
+	}
+	cp := *p
+	cp.Registers = make([]RegisterDef, len(p.Registers))
+	copy(cp.Registers, p.Registers)
+	for i := range cp.Registers {
+		cp.Registers[i].ByteOrder = order
+	}
+	return &cp, nil
+}
+
+// RankByteOrders 按四种字节序分别解析同一组原始数据，按可信度从高到低排序
+// 数值落在合理范围内加分，超出范围或为 NaN/Inf 扣分，恰为 0 的数值不提供信息不计分
+func RankByteOrders(p *Profile, regData map[uint16][]byte) []ByteOrderCandidate {
+	candidates := make([]ByteOrderCandidate, 0, len(ByteOrders))
+	for _, order := range ByteOrders {
+		c := ByteOrderCandidate{ByteOrder: order, Values: make(map[string]float64)}
+		for _, d := range p.Registers {
+			raw := regData[uint16(d.Address)]
+			if raw == nil {
+				continue
+			}
+			d.ByteOrder = order
+			v, err := d.Decode(raw)
+			if err != nil {
+				continue
+			}
+			c.Values[d.Key] = v
+			c.Score += plausibility(d.Key, v)
+		}
+		candidates = append(candidates, c)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	return candidates
+}
+
+// plausibility 单个数据点的得分
+func plausibility(key string, v float64) int {
+	r, ok := plausibleRanges[key]
+	if !ok {
+		r = plausibleRange{-1e9, 1e9, 1}
+	}
+	switch {
+	case math.IsNaN(v) || math.IsInf(v, 0):
+		return -2 * r.weight
+	case v == 0:
+		return 0
+	case v >= r.min && v <= r.max:
+		return r.weight
+	default:
+		return -r.weight
+	}
+}
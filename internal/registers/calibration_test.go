@@ -0,0 +1,71 @@
+package registers
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// encodeFloat32 按字节序编码 float32，供校准测试构造原始数据
+func encodeFloat32(v float32, order string) []byte {
+	b := binary.BigEndian.AppendUint32(nil, math.Float32bits(v))
+	switch order {
+	case ByteOrderCDAB:
+		return []byte{b[2], b[3], b[0], b[1]}
+	case ByteOrderBADC:
+		return []byte{b[1], b[0], b[3], b[2]}
+	case ByteOrderDCBA:
+		return []byte{b[3], b[2], b[1], b[0]}
+	}
+	return b
+}
+
+func TestRankByteOrders(t *testing.T) {
+	profile, _ := GetProfile(DefaultProfile)
+
+	for _, order := range ByteOrders {
+		regData := map[uint16][]byte{
+			RegVoltage:     encodeFloat32(220.5, order),
+			RegCurrent:     encodeFloat32(1.234, order),
+			RegActivePower: encodeFloat32(250.7, order),
+			RegPowerFactor: encodeFloat32(0.98, order),
+			RegFrequency:   encodeFloat32(50.02, order),
+		}
+
+		candidates := RankByteOrders(profile, regData)
+		if len(candidates) != len(ByteOrders) {
+			t.Fatalf("%s: candidate count %d", order, len(candidates))
+		}
+		best := candidates[0]
+		if best.ByteOrder != order {
+			t.Fatalf("%s: best candidate %s (scores %+v)", order, best.ByteOrder, candidates)
+		}
+		if math.Abs(best.Values[KeyVoltage]-220.5) > 0.001 || math.Abs(best.Values[KeyFrequency]-50.02) > 0.001 {
+			t.Fatalf("%s: decoded values mismatch: %+v", order, best.Values)
+		}
+		if candidates[1].Score >= best.Score {
+			t.Fatalf("%s: ranking not decisive: %+v", order, candidates)
+		}
+	}
+}
+
+func TestProfileWithByteOrder(t *testing.T) {
+	profile, _ := GetProfile(DefaultProfile)
+
+	swapped, err := profile.WithByteOrder(ByteOrderCDAB)
+	if err != nil {
+		t.Fatalf("WithByteOrder failed: %v", err)
+	}
+	data := swapped.ParseElectricalData(map[uint16][]byte{RegVoltage: {0x80, 0x00, 0x43, 0x5C}})
+	if !float32AlmostEqual(data.Voltage, 220.5, 0.001) {
+		t.Fatalf("Voltage: got %v want 220.5", data.Voltage)
+	}
+	// 原描述不受影响
+	if profile.Registers[0].ByteOrder != ByteOrderABCD {
+		t.Fatalf("original profile modified: %s", profile.Registers[0].ByteOrder)
+	}
+
+	if _, err := profile.WithByteOrder("XYZW"); err == nil {
+		t.Fatalf("expected error for unknown byte order")
+	}
+}
@@ -0,0 +1,113 @@
+package registers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// 寄存器数据类型
+const (
+	TypeInt16   = "int16"
+	TypeUint16  = "uint16"
+	TypeInt32   = "int32"
+	TypeUint32  = "uint32"
+	TypeInt64   = "int64"
+	TypeFloat32 = "float32"
+	TypeFloat64 = "float64"
+)
+
+// 字节/字序，以 32 位值 0xAABBCCDD 为例描述线上字节顺序
+const (
+	ByteOrderABCD = "ABCD" // 大端序，高字在前（Modbus 标准）
+	ByteOrderCDAB = "CDAB" // 字交换：低字在前，字内大端
+	ByteOrderBADC = "BADC" // 字内字节交换，高字在前
+	ByteOrderDCBA = "DCBA" // 小端序
+)
+
+// typeWords 各数据类型占用的寄存器数
+var typeWords = map[string]int{
+	TypeInt16:   1,
+	TypeUint16:  1,
+	TypeInt32:   2,
+	TypeUint32:  2,
+	TypeInt64:   4,
+	TypeFloat32: 2,
+	TypeFloat64: 4,
+}
+
+// WordCount 返回数据类型占用的寄存器数
+func WordCount(dataType string) (int, bool) {
+	n, ok := typeWords[dataType]
+	return n, ok
+}
+
+// ValidByteOrder 检查字节序名称是否受支持
+func ValidByteOrder(order string) bool {
+	switch order {
+	case ByteOrderABCD, ByteOrderCDAB, ByteOrderBADC, ByteOrderDCBA:
+		return true
+	}
+	return false
+}
+
+// ToBigEndian 按字节序将线上字节重排为大端序（ABCD），长度须为偶数
+// 16/64 位值按同样规则推广：CDAB 反转字顺序，BADC 交换字内字节，DCBA 整体反转
+func ToBigEndian(raw []byte, order string) ([]byte, error) {
+	if len(raw)%2 != 0 {
+		return nil, fmt.Errorf("数据长度必须为偶数: %d", len(raw))
+	}
+	n := len(raw)
+	out := make([]byte, n)
+	switch order {
+	case ByteOrderABCD:
+		copy(out, raw)
+	case ByteOrderCDAB:
+		for i := 0; i < n; i += 2 {
+			out[i], out[i+1] = raw[n-2-i], raw[n-1-i]
+		}
+	case ByteOrderBADC:
+		for i := 0; i < n; i += 2 {
+			out[i], out[i+1] = raw[i+1], raw[i]
+		}
+	case ByteOrderDCBA:
+		for i := 0; i < n; i++ {
+			out[i] = raw[n-1-i]
+		}
+	default:
+		return nil, fmt.Errorf("不支持的字节序: %s", order)
+	}
+	return out, nil
+}
+
+// DecodeValue 按数据类型与字节序解析寄存器原始字节（未缩放）
+func DecodeValue(raw []byte, dataType string, order string) (float64, error) {
+	words, ok := WordCount(dataType)
+	if !ok {
+		return 0, fmt.Errorf("不支持的数据类型: %s", dataType)
+	}
+	if len(raw) < words*2 {
+		return 0, fmt.Errorf("%s 数据长度不足: %d", dataType, len(raw))
+	}
+	b, err := ToBigEndian(raw[:words*2], order)
+	if err != nil {
+		return 0, err
+	}
+
+	switch dataType {
+	case TypeInt16:
+		return float64(int16(binary.BigEndian.Uint16(b))), nil
+	case TypeUint16:
+		return float64(binary.BigEndian.Uint16(b)), nil
+	case TypeInt32:
+		return float64(int32(binary.BigEndian.Uint32(b))), nil
+	case TypeUint32:
+		return float64(binary.BigEndian.Uint32(b)), nil
+	case TypeInt64:
+		return float64(int64(binary.BigEndian.Uint64(b))), nil
+	case TypeFloat32:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	default: // TypeFloat64
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	}
+}
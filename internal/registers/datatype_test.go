@@ -0,0 +1,108 @@
+package registers
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecodeValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      []byte
+		dataType string
+		order    string
+		want     float64
+	}{
+		{"float32 ABCD", []byte{0x43, 0x5C, 0x80, 0x00}, TypeFloat32, ByteOrderABCD, 220.5},
+		{"float32 CDAB", []byte{0x80, 0x00, 0x43, 0x5C}, TypeFloat32, ByteOrderCDAB, 220.5},
+		{"float32 BADC", []byte{0x5C, 0x43, 0x00, 0x80}, TypeFloat32, ByteOrderBADC, 220.5},
+		{"float32 DCBA", []byte{0x00, 0x80, 0x5C, 0x43}, TypeFloat32, ByteOrderDCBA, 220.5},
+		{"int16 ABCD", []byte{0xFF, 0xFE}, TypeInt16, ByteOrderABCD, -2},
+		{"int16 CDAB", []byte{0xFF, 0xFE}, TypeInt16, ByteOrderCDAB, -2},
+		{"int16 BADC", []byte{0xFE, 0xFF}, TypeInt16, ByteOrderBADC, -2},
+		{"uint16 ABCD", []byte{0x08, 0x9D}, TypeUint16, ByteOrderABCD, 2205},
+		{"uint16 DCBA", []byte{0x9D, 0x08}, TypeUint16, ByteOrderDCBA, 2205},
+		{"int32 ABCD", []byte{0xFF, 0xFE, 0x79, 0x60}, TypeInt32, ByteOrderABCD, -100000},
+		{"int32 CDAB", []byte{0x79, 0x60, 0xFF, 0xFE}, TypeInt32, ByteOrderCDAB, -100000},
+		{"uint32 BADC", []byte{0x5B, 0x07, 0x15, 0xCD}, TypeUint32, ByteOrderBADC, 123456789},
+		{"uint32 DCBA", []byte{0x15, 0xCD, 0x5B, 0x07}, TypeUint32, ByteOrderDCBA, 123456789},
+		{"int64 ABCD", []byte{0x00, 0x00, 0x01, 0x1F, 0x71, 0xFB, 0x04, 0xCB}, TypeInt64, ByteOrderABCD, 1234567890123},
+		{"int64 CDAB", []byte{0x04, 0xCB, 0x71, 0xFB, 0x01, 0x1F, 0x00, 0x00}, TypeInt64, ByteOrderCDAB, 1234567890123},
+		{"float64 ABCD", []byte{0x40, 0x6C, 0xC8, 0x00, 0x00, 0x00, 0x00, 0x00}, TypeFloat64, ByteOrderABCD, 230.25},
+		{"float64 DCBA", []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0xC8, 0x6C, 0x40}, TypeFloat64, ByteOrderDCBA, 230.25},
+	}
+
+	for _, tt := range tests {
+		got, err := DecodeValue(tt.raw, tt.dataType, tt.order)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%s: got %v want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeValue_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      []byte
+		dataType string
+		order    string
+	}{
+		{"unknown type", []byte{0x00, 0x01}, "decimal", ByteOrderABCD},
+		{"unknown order", []byte{0x00, 0x01}, TypeUint16, "ACBD"},
+		{"short data", []byte{0x43, 0x5C}, TypeFloat32, ByteOrderABCD},
+	}
+
+	for _, tt := range tests {
+		if _, err := DecodeValue(tt.raw, tt.dataType, tt.order); err == nil {
+			t.Fatalf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestRegisterDefDecode_Scaling(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		raw  []byte
+		want float64
+	}{
+		{"uint16 decimal scale", `{"key": "voltage", "address": 0, "type": "uint16", "scale": 0.1}`, []byte{0x08, 0x9D}, 220.5},
+		{"int32 word swapped", `{"key": "activePower", "address": 0, "type": "int32", "byteOrder": "CDAB", "scale": 0.001}`, []byte{0x79, 0x60, 0xFF, 0xFE}, -100},
+		{"int16 with offset", `{"key": "frequency", "address": 0, "type": "int16", "scale": 0.01, "offset": 45}`, []byte{0x01, 0xF4}, 50},
+		{"float64 energy", `{"key": "activeEnergy", "address": 0, "type": "float64", "group": "energy"}`, []byte{0x40, 0x6C, 0xC8, 0x00, 0x00, 0x00, 0x00, 0x00}, 230.25},
+	}
+
+	for _, tt := range tests {
+		p, err := ParseProfile([]byte(`{"name": "T", "registers": [` + tt.src + `]}`))
+		if err != nil {
+			t.Fatalf("%s: ParseProfile failed: %v", tt.name, err)
+		}
+		got, err := p.Registers[0].Decode(tt.raw)
+		if err != nil {
+			t.Fatalf("%s: Decode failed: %v", tt.name, err)
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Fatalf("%s: got %v want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseProfile_WordsFromType(t *testing.T) {
+	p, err := ParseProfile([]byte(`{"name": "T", "registers": [
+		{"key": "voltage", "address": 0, "type": "uint16"},
+		{"key": "activeEnergy", "address": 2, "type": "int64"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseProfile failed: %v", err)
+	}
+	if p.Registers[0].Words != 1 || p.Registers[1].Words != 4 {
+		t.Fatalf("words not derived from type: %+v", p.Registers)
+	}
+
+	if _, err := ParseProfile([]byte(`{"name": "T", "registers": [{"key": "voltage", "address": 0, "byteOrder": "XYZW"}]}`)); err == nil {
+		t.Fatalf("expected error for unknown byte order")
+	}
+}
@@ -0,0 +1,353 @@
+package registers
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"DDSUViewer/internal/modbus"
+)
+
+// 轮询分组
+const (
+	GroupElectrical = "electrical" // 电参量，每个轮询周期读取
+	GroupEnergy     = "energy"     // 电能，低频读取
+)
+
+// 数据点键，对应 ElectricalData 的字段
+const (
+	KeyVoltage       = "voltage"
+	KeyCurrent       = "current"
+	KeyActivePower   = "activePower"
+	KeyReactivePower = "reactivePower"
+	KeyApparentPower = "apparentPower"
+	KeyPowerFactor   = "powerFactor"
+	KeyFrequency     = "frequency"
+	KeyActiveEnergy  = "activeEnergy"
+
+	KeyForwardActiveEnergy   = "forwardActiveEnergy"   // 正向有功电能（进口）
+	KeyReverseActiveEnergy   = "reverseActiveEnergy"   // 反向有功电能（出口）
+	KeyForwardReactiveEnergy = "forwardReactiveEnergy" // 正向无功电能
+	KeyReverseReactiveEnergy = "reverseReactiveEnergy" // 反向无功电能
+)
+
+// DefaultProfile 内置的默认设备型号
+const DefaultProfile = "DDSU666"
+
+// DefaultMaxGap 合并读取时缺省允许跨越的未定义寄存器数
+// 9600bps 下多读一个寄存器约 2.3ms，多一次事务约需 20ms 以上（请求、帧间静默与电表处理时间）
+const DefaultMaxGap = 8
+
+// RegisterAddress 寄存器地址，JSON 中可写作数字或 "0x2000" 形式的字符串
+type RegisterAddress uint16
+
+// UnmarshalJSON 支持十进制数字与十六进制字符串
+func (a *RegisterAddress) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	v, err := strconv.ParseUint(text, 0, 16)
+	if err != nil {
+		return fmt.Errorf("寄存器地址无效: %s", string(data))
+	}
+	*a = RegisterAddress(v)
+	return nil
+}
+
+// MarshalJSON 以十六进制字符串输出
+func (a RegisterAddress) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"0x%04X"`, uint16(a))), nil
+}
+
+// RegisterDef 设备描述中的一个数据点
+type RegisterDef struct {
+	Key       string          `json:"key"`       // 数据点键，如 "voltage"
+	Function  byte            `json:"function"`  // 读功能码 0x03/0x04，缺省沿用 Profile.Function
+	Name      string          `json:"name"`      // 显示名称
+	Address   RegisterAddress `json:"address"`   // 起始寄存器地址
+	Words     int             `json:"words"`     // 占用寄存器数，缺省按数据类型推导
+	DataType  string          `json:"type"`      // 数据类型，缺省 float32
+	ByteOrder string          `json:"byteOrder"` // 字节/字序，缺省 ABCD
+	Scale     float64         `json:"scale"`     // 缩放系数，缺省 1
+	Offset    float64         `json:"offset"`    // 偏移量，工程量 = 原始值 × Scale + Offset
+	Unit      string          `json:"unit"`      // 单位
+	Group     string          `json:"group"`     // 轮询分组，缺省 electrical
+}
+
+// Decode 将寄存器原始字节转换为工程量
+func (d RegisterDef) Decode(raw []byte) (float64, error) {
+	v, err := DecodeValue(raw, d.DataType, d.ByteOrder)
+	if err != nil {
+		return 0, fmt.Errorf("数据点 %s: %w", d.Key, err)
+	}
+	return v*d.Scale + d.Offset, nil
+}
+
+// Profile 设备描述：寄存器布局与数据点定义
+type Profile struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Phases      int           `json:"phases"`     // 相数：1 为单相（ElectricalData），3 为三相（ThreePhaseData）
+	Function    byte          `json:"function"`   // 缺省读功能码：3 读保持寄存器（缺省），4 读输入寄存器
+	Parameters  string        `json:"parameters"` // 参数寄存器布局，空值表示不支持参数读写
+	Registers   []RegisterDef `json:"registers"`
+
+	// 合并读取限制：读取未定义地址会返回异常的电表应将 maxGap 设为 0
+	MaxRegisters int  `json:"maxRegisters"` // 单次读取最多寄存器数，缺省 125
+	MaxGap       *int `json:"maxGap"`       // 允许一并读取的未定义寄存器数，缺省 DefaultMaxGap
+}
+
+// ParseProfile 解析 JSON 设备描述并补全缺省值
+func ParseProfile(data []byte) (*Profile, error) {
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("解析设备描述失败: %w", err)
+	}
+	if err := p.normalize(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// normalize 补全缺省值并检查定义合法性
+func (p *Profile) normalize() error {
+	if p.Name == "" {
+		return fmt.Errorf("设备描述缺少名称")
+	}
+	if len(p.Registers) == 0 {
+		return fmt.Errorf("设备描述 %s 未定义数据点", p.Name)
+	}
+	if p.Phases == 0 {
+		p.Phases = SinglePhase
+	}
+	if p.Phases != SinglePhase && p.Phases != ThreePhase {
+		return fmt.Errorf("设备描述 %s 相数无效: %d", p.Name, p.Phases)
+	}
+	if p.Function == 0 {
+		p.Function = modbus.FunctionReadHoldingRegisters
+	}
+	if p.MaxRegisters == 0 {
+		p.MaxRegisters = modbus.MaxReadRegisters
+	}
+	if p.MaxRegisters < 1 || p.MaxRegisters > modbus.MaxReadRegisters {
+		return fmt.Errorf("设备描述 %s 单次读取寄存器数必须在 1-%d 之间: %d", p.Name, modbus.MaxReadRegisters, p.MaxRegisters)
+	}
+	if p.MaxGap == nil {
+		gap := DefaultMaxGap
+		p.MaxGap = &gap
+	}
+	if *p.MaxGap < 0 || *p.MaxGap >= p.MaxRegisters {
+		return fmt.Errorf("设备描述 %s 允许跨越的寄存器数无效: %d", p.Name, *p.MaxGap)
+	}
+
+	seen := make(map[string]bool)
+	for i := range p.Registers {
+		d := &p.Registers[i]
+		if d.Key == "" {
+			return fmt.Errorf("设备描述 %s 第 %d 个数据点缺少 key", p.Name, i+1)
+		}
+		if seen[d.Key] {
+			return fmt.Errorf("设备描述 %s 数据点重复: %s", p.Name, d.Key)
+		}
+		seen[d.Key] = true
+
+		if d.Name == "" {
+			d.Name = d.Key
+		}
+		if d.DataType == "" {
+			d.DataType = TypeFloat32
+		}
+		if d.ByteOrder == "" {
+			d.ByteOrder = ByteOrderABCD
+		}
+		if d.Scale == 0 {
+			d.Scale = 1
+		}
+		if d.Group == "" {
+			d.Group = GroupElectrical
+		}
+		if d.Function == 0 {
+			d.Function = p.Function
+		}
+		if d.Function != modbus.FunctionReadHoldingRegisters && d.Function != modbus.FunctionReadInputRegisters {
+			return fmt.Errorf("数据点 %s 不支持的读功能码: 0x%02X", d.Key, d.Function)
+		}
+
+		words, ok := WordCount(d.DataType)
+		if !ok {
+			return fmt.Errorf("数据点 %s 不支持的数据类型: %s", d.Key, d.DataType)
+		}
+		if !ValidByteOrder(d.ByteOrder) {
+			return fmt.Errorf("数据点 %s 不支持的字节序: %s", d.Key, d.ByteOrder)
+		}
+		if d.Words == 0 {
+			d.Words = words
+		}
+		if d.Words != words {
+			return fmt.Errorf("数据点 %s 寄存器数与类型 %s 不符: %d", d.Key, d.DataType, d.Words)
+		}
+	}
+	return nil
+}
+
+// ReadLimits 返回合并读取限制，未经 ParseProfile 补全的描述按缺省值处理
+func (p *Profile) ReadLimits() (maxRegisters, maxGap int) {
+	maxRegisters, maxGap = p.MaxRegisters, DefaultMaxGap
+	if maxRegisters <= 0 || maxRegisters > modbus.MaxReadRegisters {
+		maxRegisters = modbus.MaxReadRegisters
+	}
+	if p.MaxGap != nil {
+		maxGap = *p.MaxGap
+	}
+	return maxRegisters, maxGap
+}
+
+// Group 返回指定分组的数据点，按地址升序
+func (p *Profile) Group(name string) []RegisterDef {
+	var defs []RegisterDef
+	for _, d := range p.Registers {
+		if d.Group == name {
+			defs = append(defs, d)
+		}
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Address < defs[j].Address })
+	return defs
+}
+
+// Has 判断描述中是否定义了指定数据点
+func (p *Profile) Has(key string) bool {
+	for _, d := range p.Registers {
+		if d.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Groups 返回描述中出现的全部分组，按首次出现顺序
+func (p *Profile) Groups() []string {
+	var groups []string
+	seen := make(map[string]bool)
+	for _, d := range p.Registers {
+		if !seen[d.Group] {
+			seen[d.Group] = true
+			groups = append(groups, d.Group)
+		}
+	}
+	return groups
+}
+
+// DataPoints 根据描述生成数据点列表
+func (p *Profile) DataPoints() []DataPoint {
+	points := make([]DataPoint, 0, len(p.Registers))
+	for _, d := range p.Registers {
+		points = append(points, DataPoint{Key: d.Key, Name: d.Name, Address: uint16(d.Address), Unit: d.Unit})
+	}
+	return points
+}
+
+// DecodeValues 按描述解析 地址->原始字节 映射，返回 数据点键->工程量，缺失或无法解析的数据点不出现在结果中
+func (p *Profile) DecodeValues(regData map[uint16][]byte) map[string]float64 {
+	values := make(map[string]float64, len(p.Registers))
+	for _, d := range p.Registers {
+		raw := regData[uint16(d.Address)]
+		if raw == nil {
+			continue
+		}
+		v, err := d.Decode(raw)
+		if err != nil {
+			continue
+		}
+		values[d.Key] = v
+	}
+	return values
+}
+
+// ParseElectricalData 按描述解析单相电参量，缺失或无法解析的数据点保持零值
+func (p *Profile) ParseElectricalData(regData map[uint16][]byte) *ElectricalData {
+	data := &ElectricalData{}
+	for key, v := range p.DecodeValues(regData) {
+		data.SetField(key, float32(v))
+	}
+	return data
+}
+
+//go:embed profiles/*.json
+var embeddedProfiles embed.FS
+
+var (
+	profileMutex sync.RWMutex
+	profiles     = make(map[string]*Profile)
+)
+
+func init() {
+	entries, err := embeddedProfiles.ReadDir("profiles")
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range entries {
+		data, err := embeddedProfiles.ReadFile("profiles/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		p, err := ParseProfile(data)
+		if err != nil {
+			panic(fmt.Sprintf("内置设备描述 %s 无效: %v", e.Name(), err))
+		}
+		profiles[p.Name] = p
+	}
+}
+
+// RegisterProfile 注册（或覆盖）一个设备描述
+func RegisterProfile(p *Profile) {
+	profileMutex.Lock()
+	defer profileMutex.Unlock()
+	profiles[p.Name] = p
+}
+
+// LoadProfileDir 加载目录下全部 *.json 设备描述，目录不存在时忽略
+func LoadProfileDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		p, err := ParseProfile(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+		RegisterProfile(p)
+	}
+	return nil
+}
+
+// GetProfile 按名称查找设备描述
+func GetProfile(name string) (*Profile, bool) {
+	profileMutex.RLock()
+	defer profileMutex.RUnlock()
+	p, ok := profiles[name]
+	return p, ok
+}
+
+// ProfileNames 返回已注册的设备描述名称，按字母排序
+func ProfileNames() []string {
+	profileMutex.RLock()
+	defer profileMutex.RUnlock()
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
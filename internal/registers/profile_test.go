@@ -0,0 +1,168 @@
+package registers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEmbeddedDDSU666Profile(t *testing.T) {
+	p, ok := GetProfile(DefaultProfile)
+	if !ok {
+		t.Fatalf("embedded profile %s not registered", DefaultProfile)
+	}
+
+	want := map[string]uint16{
+		KeyVoltage:       RegVoltage,
+		KeyCurrent:       RegCurrent,
+		KeyActivePower:   RegActivePower,
+		KeyReactivePower: RegReactivePower,
+		KeyApparentPower: RegApparentPower,
+		KeyPowerFactor:   RegPowerFactor,
+		KeyFrequency:     RegFrequency,
+		KeyActiveEnergy:  RegActiveEnergy,
+	}
+	if len(p.Registers) != len(want) {
+		t.Fatalf("register count: got %d want %d", len(p.Registers), len(want))
+	}
+	for _, d := range p.Registers {
+		if uint16(d.Address) != want[d.Key] {
+			t.Fatalf("%s address: got 0x%04X want 0x%04X", d.Key, uint16(d.Address), want[d.Key])
+		}
+		if d.Words != 2 || d.Scale != 1 {
+			t.Fatalf("%s defaults not applied: %+v", d.Key, d)
+		}
+	}
+
+	energy := p.Group(GroupEnergy)
+	if len(energy) != 1 || energy[0].Key != KeyActiveEnergy {
+		t.Fatalf("energy group mismatch: %+v", energy)
+	}
+}
+
+func TestCustomProfileDataPoints(t *testing.T) {
+	custom, err := ParseProfile([]byte(`{
+		"name": "TEST-METER",
+		"registers": [
+			{"key": "voltage", "name": "U", "address": 256, "unit": "V", "scale": 0.1}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("ParseProfile failed: %v", err)
+	}
+	RegisterProfile(custom)
+	if p, ok := GetProfile("TEST-METER"); !ok || p != custom {
+		t.Fatalf("custom profile not registered")
+	}
+
+	points := custom.DataPoints()
+	if len(points) != 1 || points[0].Address != 0x0100 || points[0].Name != "U" {
+		t.Fatalf("data points mismatch: %+v", points)
+	}
+
+	data := custom.ParseElectricalData(map[uint16][]byte{0x0100: {0x43, 0x5C, 0x80, 0x00}})
+	if !float32AlmostEqual(data.Voltage, 22.05, 0.001) {
+		t.Fatalf("scaled voltage: got %v want 22.05", data.Voltage)
+	}
+}
+
+func TestParseProfile_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"missing name":   `{"registers": [{"key": "voltage", "address": "0x2000"}]}`,
+		"no registers":   `{"name": "X"}`,
+		"duplicate key":  `{"name": "X", "registers": [{"key": "voltage", "address": 1}, {"key": "voltage", "address": 3}]}`,
+		"bad address":    `{"name": "X", "registers": [{"key": "voltage", "address": "0x1FFFF"}]}`,
+		"unknown type":   `{"name": "X", "registers": [{"key": "voltage", "address": 1, "type": "decimal"}]}`,
+		"words mismatch": `{"name": "X", "registers": [{"key": "voltage", "address": 1, "words": 4}]}`,
+	}
+	for name, src := range cases {
+		if _, err := ParseProfile([]byte(src)); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadProfileDir(t *testing.T) {
+	dir := t.TempDir()
+	src := `{"name": "DIR-METER", "registers": [{"key": "frequency", "address": "0x0010", "unit": "Hz"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "dir.json"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadProfileDir(dir); err != nil {
+		t.Fatalf("LoadProfileDir failed: %v", err)
+	}
+	p, ok := GetProfile("DIR-METER")
+	if !ok || uint16(p.Registers[0].Address) != 0x0010 {
+		t.Fatalf("profile not loaded: %+v", p)
+	}
+
+	if err := LoadProfileDir(filepath.Join(dir, "missing")); err != nil {
+		t.Fatalf("missing directory should be ignored: %v", err)
+	}
+}
+
+func TestEmbeddedVendorProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		function byte
+	}{
+		{"SDM120", 0x04},
+		{"SDM230", 0x04},
+		{"ADL200", 0x03},
+	}
+
+	for _, tt := range tests {
+		p, ok := GetProfile(tt.name)
+		if !ok {
+			t.Fatalf("%s: profile not registered", tt.name)
+		}
+		var data ElectricalData
+		for _, d := range p.Registers {
+			if d.Function != tt.function {
+				t.Fatalf("%s: %s function got 0x%02X want 0x%02X", tt.name, d.Key, d.Function, tt.function)
+			}
+			// 第三方电表映射到同一 ElectricalData 模型
+			if !data.SetField(d.Key, 1) {
+				t.Fatalf("%s: key %s has no ElectricalData field", tt.name, d.Key)
+			}
+		}
+		if p.Parameters != "" {
+			t.Fatalf("%s: vendor profile must not allow Chint parameter writes", tt.name)
+		}
+	}
+
+	sdm, _ := GetProfile("SDM120")
+	ddsu, _ := GetProfile(DefaultProfile)
+	if !sdm.Has(KeyReverseActiveEnergy) || ddsu.Has(KeyReverseActiveEnergy) {
+		t.Fatalf("directional energy should only be defined where the meter provides it")
+	}
+
+	if _, err := ParseProfile([]byte(`{"name": "X", "function": 1, "registers": [{"key": "voltage", "address": 0}]}`)); err == nil {
+		t.Fatalf("expected error for unsupported function code")
+	}
+}
+
+func TestProfileReadLimits(t *testing.T) {
+	p, err := ParseProfile([]byte(`{"name": "X", "registers": [{"key": "voltage", "address": 0}]}`))
+	if err != nil {
+		t.Fatalf("ParseProfile failed: %v", err)
+	}
+	if maxRegs, maxGap := p.ReadLimits(); maxRegs != 125 || maxGap != DefaultMaxGap {
+		t.Fatalf("default limits got %d/%d", maxRegs, maxGap)
+	}
+
+	p, err = ParseProfile([]byte(`{"name": "X", "maxRegisters": 40, "maxGap": 0, "registers": [{"key": "voltage", "address": 0}]}`))
+	if err != nil {
+		t.Fatalf("ParseProfile failed: %v", err)
+	}
+	if maxRegs, maxGap := p.ReadLimits(); maxRegs != 40 || maxGap != 0 {
+		t.Fatalf("explicit limits got %d/%d", maxRegs, maxGap)
+	}
+
+	for _, bad := range []string{`"maxRegisters": 126`, `"maxGap": -1`, `"maxRegisters": 10, "maxGap": 10`} {
+		if _, err := ParseProfile([]byte(`{"name": "X", ` + bad + `, "registers": [{"key": "voltage", "address": 0}]}`)); err == nil {
+			t.Fatalf("%s: expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package registers
+
+import (
+	"math"
+	"time"
+)
+
+// Quality 数据点质量
+type Quality string
+
+const (
+	QualityGood       Quality = "good"        // 所在分组最近一次按计划读取成功，数值有效
+	QualityStale      Quality = "stale"       // 本次读取失败，沿用上次读到的值
+	QualityInvalid    Quality = "invalid"     // 读取成功但无法解码或为 NaN/Inf，数值置 0
+	QualityCommFailed Quality = "comm-failed" // 读取失败且没有可沿用的值，数值为 0
+)
+
+// Acquisition 一次轮询结果的采集信息
+type Acquisition struct {
+	Timestamp time.Time          // 本次读取完成的时间
+	Latency   time.Duration      // 本次成功读取事务的往返耗时之和
+	Quality   map[string]Quality // 数据点键 -> 质量，未读取过的按需分组不列出
+	Err       error              // 本次读取中的第一个错误，全部成功时为 nil
+}
+
+// Stale 是否有数据点不是有效的最新值，界面据此将数值置灰
+func (a *Acquisition) Stale() bool {
+	for _, q := range a.Quality {
+		if q != QualityGood {
+			return true
+		}
+	}
+	return false
+}
+
+// AssessQuality 按分组读取结果评估各数据点质量
+// attempted 为本次尝试读取的分组，read 为其中读取成功的分组，last 为上次结果的质量：
+// 读取成功的分组按解码结果为 good 或 invalid，无效数据点的原始数据从 regData 中移除，解析时按缺失处理；
+// 读取失败的分组有上次值时为 stale，否则为 comm-failed；本次未读取的分组沿用上次的质量
+func (p *Profile) AssessQuality(regData map[uint16][]byte, attempted, read map[string]bool, last map[string]Quality) map[string]Quality {
+	quality := make(map[string]Quality, len(p.Registers))
+	for _, d := range p.Registers {
+		switch {
+		case read[d.Group]:
+			raw := regData[uint16(d.Address)]
+			v, err := d.Decode(raw)
+			if raw == nil || err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+				quality[d.Key] = QualityInvalid
+				delete(regData, uint16(d.Address))
+			} else {
+				quality[d.Key] = QualityGood
+			}
+		case attempted[d.Group]:
+			if q, ok := last[d.Key]; ok && q != QualityCommFailed && q != QualityInvalid {
+				quality[d.Key] = QualityStale
+			} else {
+				quality[d.Key] = QualityCommFailed
+			}
+		default:
+			if q, ok := last[d.Key]; ok {
+				quality[d.Key] = q
+			}
+		}
+	}
+	return quality
+}
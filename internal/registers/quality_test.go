@@ -0,0 +1,47 @@
+package registers
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestAssessQuality(t *testing.T) {
+	profile, ok := GetProfile(DefaultProfile)
+	if !ok {
+		t.Fatalf("default profile missing")
+	}
+
+	regData := make(map[uint16][]byte)
+	for _, d := range profile.Group(GroupElectrical) {
+		regData[uint16(d.Address)] = binary.BigEndian.AppendUint32(nil, math.Float32bits(1))
+	}
+	regData[RegCurrent] = binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(math.NaN())))
+
+	attempted := map[string]bool{GroupElectrical: true, GroupEnergy: true}
+	read := map[string]bool{GroupElectrical: true}
+	quality := profile.AssessQuality(regData, attempted, read, nil)
+
+	if quality[KeyVoltage] != QualityGood {
+		t.Fatalf("voltage: %s", quality[KeyVoltage])
+	}
+	if quality[KeyCurrent] != QualityInvalid {
+		t.Fatalf("current: %s", quality[KeyCurrent])
+	}
+	if _, ok := regData[RegCurrent]; ok {
+		t.Fatalf("invalid raw data should be removed")
+	}
+	if quality[KeyActiveEnergy] != QualityCommFailed {
+		t.Fatalf("energy without previous value: %s", quality[KeyActiveEnergy])
+	}
+
+	// 有上次值时读取失败为 stale；本次未读取的分组沿用上次的质量
+	last := quality
+	quality = profile.AssessQuality(map[uint16][]byte{}, map[string]bool{GroupElectrical: true}, nil, last)
+	if quality[KeyVoltage] != QualityStale || quality[KeyCurrent] != QualityCommFailed || quality[KeyActiveEnergy] != QualityCommFailed {
+		t.Fatalf("unexpected quality after failure: %v", quality)
+	}
+	if a := (Acquisition{Quality: map[string]Quality{KeyVoltage: QualityGood}}); a.Stale() {
+		t.Fatalf("all-good acquisition should not be stale")
+	}
+}
@@ -0,0 +1,67 @@
+package registers
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+)
+
+// RawRegister 单个寄存器的多种解读
+type RawRegister struct {
+	Address uint16
+	Uint16  uint16
+	Int16   int16
+	Float32 map[string]float64 // 字节序 -> 以本寄存器起始的 float32，NaN/Inf 与最后一个寄存器不给出
+}
+
+// RawReading 任意地址区间的原始读取结果
+type RawReading struct {
+	Function  byte
+	Start     uint16
+	Words     []uint16
+	ASCII     string // 按线上字节顺序解释为 ASCII，不可打印字符以 '.' 代替
+	Registers []RawRegister
+}
+
+// InterpretWords 将连续寄存器解释为 uint16、int16、各字节序 float32 与 ASCII
+func InterpretWords(function byte, start uint16, words []uint16) *RawReading {
+	raw := make([]byte, 0, len(words)*2)
+	for _, w := range words {
+		raw = binary.BigEndian.AppendUint16(raw, w)
+	}
+
+	reading := &RawReading{
+		Function:  function,
+		Start:     start,
+		Words:     words,
+		ASCII:     printableASCII(raw),
+		Registers: make([]RawRegister, len(words)),
+	}
+	for i, w := range words {
+		r := RawRegister{Address: start + uint16(i), Uint16: w, Int16: int16(w)}
+		if i+1 < len(words) {
+			r.Float32 = make(map[string]float64, len(ByteOrders))
+			for _, order := range ByteOrders {
+				v, err := DecodeValue(raw[i*2:i*2+4], TypeFloat32, order)
+				if err == nil && !math.IsNaN(v) && !math.IsInf(v, 0) {
+					r.Float32[order] = v
+				}
+			}
+		}
+		reading.Registers[i] = r
+	}
+	return reading
+}
+
+// printableASCII 将字节转换为可显示的 ASCII 文本
+func printableASCII(raw []byte) string {
+	var b strings.Builder
+	for _, c := range raw {
+		if c >= 0x20 && c < 0x7F {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('.')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package registers
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInterpretWords(t *testing.T) {
+	// 220.5 = 0x435C8000；0x4F4B = "OK"；0xFFFF = -1
+	r := InterpretWords(0x03, 0x2000, []uint16{0x435C, 0x8000, 0x4F4B, 0xFFFF})
+
+	if r.ASCII != "C\\..OK.." {
+		t.Fatalf("ASCII got %q", r.ASCII)
+	}
+	if r.Registers[3].Address != 0x2003 || r.Registers[3].Uint16 != 0xFFFF || r.Registers[3].Int16 != -1 {
+		t.Fatalf("last register got %+v", r.Registers[3])
+	}
+	if r.Registers[3].Float32 != nil {
+		t.Fatalf("last register must not have float32 interpretation")
+	}
+	if v := r.Registers[0].Float32[ByteOrderABCD]; math.Abs(v-220.5) > 1e-6 {
+		t.Fatalf("ABCD float32 got %v", v)
+	}
+	if len(r.Registers[0].Float32) != len(ByteOrders) {
+		t.Fatalf("expected all byte orders, got %v", r.Registers[0].Float32)
+	}
+	// 0x4F4B 0xFFFF 按 ABCD 为有限值，按 CDAB 为 NaN，NaN 不应出现（无法序列化为 JSON）
+	if _, ok := r.Registers[2].Float32[ByteOrderCDAB]; ok {
+		t.Fatalf("NaN interpretation must be omitted: %v", r.Registers[2].Float32)
+	}
+	// CDAB：0x8000 0x435C 按字交换后同样为 220.5
+	swapped := InterpretWords(0x03, 0, []uint16{0x8000, 0x435C})
+	if v := swapped.Registers[0].Float32[ByteOrderCDAB]; math.Abs(v-220.5) > 1e-6 {
+		t.Fatalf("CDAB float32 got %v", v)
+	}
+}
@@ -5,7 +5,8 @@ import (
 	"math"
 )
 
-// 寄存器地址定义
+// DDSU666 寄存器地址定义
+// 轮询器按设备描述（profiles/ddsu666.json）读取，这里的常量供仿真器与测试使用
 const (
 	RegVoltage       = 0x2000 // 电压 U
 	RegCurrent       = 0x2002 // 电流 I
@@ -19,6 +20,7 @@ const (
 
 // DataPoint 数据点
 type DataPoint struct {
+	Key     string
 	Name    string
 	Address uint16
 	Value   float32
@@ -35,23 +37,81 @@ type ElectricalData struct {
 	PowerFactor   float32 // 功率因数
 	Frequency     float32 // 频率 Hz
 	ActiveEnergy  float32 // 有功总电能 kWh
+
+	// 分向电能，仅部分型号提供，未定义时为 0
+	ForwardActiveEnergy   float32 // 正向有功电能 kWh
+	ReverseActiveEnergy   float32 // 反向有功电能 kWh
+	ForwardReactiveEnergy float32 // 正向无功电能 kvarh
+	ReverseReactiveEnergy float32 // 反向无功电能 kvarh
+
+	Acquisition // 采集时间、各数据点质量、错误与往返耗时
+}
+
+// SetField 按数据点键设置字段，未知键返回 false
+func (d *ElectricalData) SetField(key string, value float32) bool {
+	switch key {
+	case KeyVoltage:
+		d.Voltage = value
+	case KeyCurrent:
+		d.Current = value
+	case KeyActivePower:
+		d.ActivePower = value
+	case KeyReactivePower:
+		d.ReactivePower = value
+	case KeyApparentPower:
+		d.ApparentPower = value
+	case KeyPowerFactor:
+		d.PowerFactor = value
+	case KeyFrequency:
+		d.Frequency = value
+	case KeyActiveEnergy:
+		d.ActiveEnergy = value
+	case KeyForwardActiveEnergy:
+		d.ForwardActiveEnergy = value
+	case KeyReverseActiveEnergy:
+		d.ReverseActiveEnergy = value
+	case KeyForwardReactiveEnergy:
+		d.ForwardReactiveEnergy = value
+	case KeyReverseReactiveEnergy:
+		d.ReverseReactiveEnergy = value
+	default:
+		return false
+	}
+	return true
 }
 
-// GetDataPoints 获取所有数据点定义
-func GetDataPoints() []DataPoint {
-	return []DataPoint{
-		{Name: "电压", Address: RegVoltage, Unit: "V"},
-		{Name: "电流", Address: RegCurrent, Unit: "A"},
-		{Name: "有功功率", Address: RegActivePower, Unit: "W"},
-		{Name: "无功功率", Address: RegReactivePower, Unit: "VAR"},
-		{Name: "视在功率", Address: RegApparentPower, Unit: "VA"},
-		{Name: "功率因数", Address: RegPowerFactor, Unit: ""},
-		{Name: "频率", Address: RegFrequency, Unit: "Hz"},
-		{Name: "有功总电能", Address: RegActiveEnergy, Unit: "kWh"},
+// Field 按数据点键读取字段
+func (d *ElectricalData) Field(key string) (float32, bool) {
+	switch key {
+	case KeyVoltage:
+		return d.Voltage, true
+	case KeyCurrent:
+		return d.Current, true
+	case KeyActivePower:
+		return d.ActivePower, true
+	case KeyReactivePower:
+		return d.ReactivePower, true
+	case KeyApparentPower:
+		return d.ApparentPower, true
+	case KeyPowerFactor:
+		return d.PowerFactor, true
+	case KeyFrequency:
+		return d.Frequency, true
+	case KeyActiveEnergy:
+		return d.ActiveEnergy, true
+	case KeyForwardActiveEnergy:
+		return d.ForwardActiveEnergy, true
+	case KeyReverseActiveEnergy:
+		return d.ReverseActiveEnergy, true
+	case KeyForwardReactiveEnergy:
+		return d.ForwardReactiveEnergy, true
+	case KeyReverseReactiveEnergy:
+		return d.ReverseReactiveEnergy, true
 	}
+	return 0, false
 }
 
-// ParseFloat32 解析IEEE754浮点数
+// ParseFloat32 解析IEEE754浮点数（其他数据类型与字节序见 DecodeValue）
 // 根据DDSU666文档和测试验证，使用IEEE 754标准大端序格式
 // 测试结果：43 5C 80 00 -> 220.5V (标准大端序正确)
 func ParseFloat32(data []byte) float32 {
@@ -70,34 +130,8 @@ func IsValidFloat32(value float32) bool {
 	return !math.IsNaN(float64(value)) && !math.IsInf(float64(value), 0)
 }
 
-// ParseElectricalData 解析电参量数据
+// ParseElectricalData 按默认设备描述（DDSU666）解析电参量数据，其他型号使用 Profile.ParseElectricalData
 func ParseElectricalData(regData map[uint16][]byte) *ElectricalData {
-	data := &ElectricalData{}
-
-	if regData[RegVoltage] != nil {
-		data.Voltage = ParseFloat32(regData[RegVoltage])
-	}
-	if regData[RegCurrent] != nil {
-		data.Current = ParseFloat32(regData[RegCurrent])
-	}
-	if regData[RegActivePower] != nil {
-		data.ActivePower = ParseFloat32(regData[RegActivePower])
-	}
-	if regData[RegReactivePower] != nil {
-		data.ReactivePower = ParseFloat32(regData[RegReactivePower])
-	}
-	if regData[RegApparentPower] != nil {
-		data.ApparentPower = ParseFloat32(regData[RegApparentPower])
-	}
-	if regData[RegPowerFactor] != nil {
-		data.PowerFactor = ParseFloat32(regData[RegPowerFactor])
-	}
-	if regData[RegFrequency] != nil {
-		data.Frequency = ParseFloat32(regData[RegFrequency])
-	}
-	if regData[RegActiveEnergy] != nil {
-		data.ActiveEnergy = ParseFloat32(regData[RegActiveEnergy])
-	}
-
-	return data
-}
\ No newline at end of file
+	profile, _ := GetProfile(DefaultProfile)
+	return profile.ParseElectricalData(regData)
+}
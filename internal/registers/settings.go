@@ -0,0 +1,124 @@
+package registers
+
+import (
+	"fmt"
+)
+
+// DDSU666 参数寄存器地址定义
+const (
+	RegUCode       = 0x0000 // 编程密码 UCode（只读）
+	RegRevision    = 0x0001 // 软件版本 REV.（只读）
+	RegClearEnergy = 0x0002 // 清电量 CLrE，写入 1 清零电能
+	RegProtocol    = 0x0005 // 通讯协议选择
+	RegAddress     = 0x0006 // 通讯地址 Addr
+	RegBaudRate    = 0x000C // 波特率 bAud
+
+	SettingsStart    = RegUCode
+	SettingsQuantity = RegBaudRate - RegUCode + 1 // 0x0000-0x000C
+)
+
+// ParametersChint 正泰 DDSU/DTSU 系列参数寄存器布局（Profile.Parameters 取值）
+const ParametersChint = "chint"
+
+// 通讯协议取值
+const (
+	ProtocolDLT645    = 1 // DL/T645-2007
+	ProtocolModbusRTU = 2 // Modbus-RTU
+)
+
+// ClearEnergyCommand 写入 RegClearEnergy 以清零电能
+const ClearEnergyCommand = 1
+
+// baudRateCodes 波特率寄存器取值 -> 波特率
+var baudRateCodes = []int{1200, 2400, 4800, 9600}
+
+// MeterSettings 电表参数
+type MeterSettings struct {
+	UCode    uint16 // 编程密码
+	Revision uint16 // 软件版本
+	Protocol uint16 // 通讯协议：1=DL/T645-2007，2=Modbus-RTU
+	Address  byte   // 通讯地址 1-247
+	BaudRate int    // 波特率 bps
+}
+
+// BaudRateCode 将波特率转换为寄存器取值
+func BaudRateCode(baud int) (uint16, error) {
+	for code, b := range baudRateCodes {
+		if b == baud {
+			return uint16(code), nil
+		}
+	}
+	return 0, fmt.Errorf("电表不支持的波特率: %d", baud)
+}
+
+// BaudRateFromCode 将寄存器取值转换为波特率
+func BaudRateFromCode(code uint16) (int, error) {
+	if int(code) >= len(baudRateCodes) {
+		return 0, fmt.Errorf("未知的波特率代码: %d", code)
+	}
+	return baudRateCodes[code], nil
+}
+
+// ParseMeterSettings 解析从 SettingsStart 起读取的 SettingsQuantity 个参数寄存器
+func ParseMeterSettings(regs []uint16) (*MeterSettings, error) {
+	if len(regs) < SettingsQuantity {
+		return nil, fmt.Errorf("参数寄存器数量不足: %d", len(regs))
+	}
+	baud, err := BaudRateFromCode(regs[RegBaudRate-SettingsStart])
+	if err != nil {
+		return nil, err
+	}
+	return &MeterSettings{
+		UCode:    regs[RegUCode-SettingsStart],
+		Revision: regs[RegRevision-SettingsStart],
+		Protocol: regs[RegProtocol-SettingsStart],
+		Address:  byte(regs[RegAddress-SettingsStart]),
+		BaudRate: baud,
+	}, nil
+}
+
+// Validate 检查可写参数取值
+func (m *MeterSettings) Validate() error {
+	if m.Address < 1 || m.Address > 247 {
+		return fmt.Errorf("通讯地址必须在 1-247 之间: %d", m.Address)
+	}
+	if _, err := BaudRateCode(m.BaudRate); err != nil {
+		return err
+	}
+	if m.Protocol != ProtocolModbusRTU {
+		// 切换到 DL/T645 后电表不再响应 Modbus 请求，本软件无法再读写
+		return fmt.Errorf("不允许切换通讯协议: %d（仅支持 Modbus-RTU）", m.Protocol)
+	}
+	return nil
+}
+
+// SettingsWrite 一次写多个寄存器 (0x10) 操作
+type SettingsWrite struct {
+	Address uint16
+	Values  []uint16
+}
+
+// PlanSettingsWrites 对比当前与目标参数，生成需要写入的寄存器
+// 只读参数被修改或目标取值非法时返回错误
+// 写入顺序为 协议 -> 地址 -> 波特率：地址写入后的请求需改用新地址，波特率写入后需按新波特率重连
+func PlanSettingsWrites(current, desired *MeterSettings) ([]SettingsWrite, error) {
+	if desired.UCode != current.UCode || desired.Revision != current.Revision {
+		return nil, fmt.Errorf("编程密码与软件版本为只读参数")
+	}
+	if err := desired.Validate(); err != nil {
+		return nil, err
+	}
+
+	var writes []SettingsWrite
+	if desired.Protocol != current.Protocol {
+		writes = append(writes, SettingsWrite{Address: RegProtocol, Values: []uint16{desired.Protocol}})
+	}
+	if desired.Address != current.Address {
+		writes = append(writes, SettingsWrite{Address: RegAddress, Values: []uint16{uint16(desired.Address)}})
+	}
+	if desired.BaudRate != current.BaudRate {
+		code, _ := BaudRateCode(desired.BaudRate)
+		writes = append(writes, SettingsWrite{Address: RegBaudRate, Values: []uint16{code}})
+	}
+	return writes, nil
+}
@@ -0,0 +1,72 @@
+package registers
+
+import "testing"
+
+func TestParseMeterSettings(t *testing.T) {
+	regs := make([]uint16, SettingsQuantity)
+	regs[RegUCode] = 0x0701
+	regs[RegRevision] = 0x0100
+	regs[RegProtocol] = ProtocolModbusRTU
+	regs[RegAddress] = 0x0C
+	regs[RegBaudRate] = 3
+
+	m, err := ParseMeterSettings(regs)
+	if err != nil {
+		t.Fatalf("ParseMeterSettings failed: %v", err)
+	}
+	if m.UCode != 0x0701 || m.Revision != 0x0100 || m.Protocol != ProtocolModbusRTU || m.Address != 0x0C || m.BaudRate != 9600 {
+		t.Fatalf("settings mismatch: %+v", m)
+	}
+
+	regs[RegBaudRate] = 7
+	if _, err := ParseMeterSettings(regs); err == nil {
+		t.Fatalf("expected error for unknown baud code")
+	}
+	if _, err := ParseMeterSettings(regs[:4]); err == nil {
+		t.Fatalf("expected error for short data")
+	}
+}
+
+func TestPlanSettingsWrites(t *testing.T) {
+	current := &MeterSettings{UCode: 1, Revision: 0x0100, Protocol: ProtocolModbusRTU, Address: 0x0C, BaudRate: 9600}
+
+	tests := []struct {
+		name    string
+		modify  func(m *MeterSettings)
+		want    []SettingsWrite
+		wantErr bool
+	}{
+		{"unchanged", func(m *MeterSettings) {}, nil, false},
+		{"address only", func(m *MeterSettings) { m.Address = 0x20 }, []SettingsWrite{{RegAddress, []uint16{0x20}}}, false},
+		{"address then baud", func(m *MeterSettings) { m.Address = 0x20; m.BaudRate = 2400 },
+			[]SettingsWrite{{RegAddress, []uint16{0x20}}, {RegBaudRate, []uint16{1}}}, false},
+		{"address out of range", func(m *MeterSettings) { m.Address = 248 }, nil, true},
+		{"address zero", func(m *MeterSettings) { m.Address = 0 }, nil, true},
+		{"unsupported baud", func(m *MeterSettings) { m.BaudRate = 19200 }, nil, true},
+		{"switch to DL/T645", func(m *MeterSettings) { m.Protocol = ProtocolDLT645 }, nil, true},
+		{"read-only revision", func(m *MeterSettings) { m.Revision = 0x0200 }, nil, true},
+	}
+
+	for _, tt := range tests {
+		desired := *current
+		tt.modify(&desired)
+		got, err := PlanSettingsWrites(current, &desired)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: got %+v want %+v", tt.name, got, tt.want)
+		}
+		for i := range got {
+			if got[i].Address != tt.want[i].Address || got[i].Values[0] != tt.want[i].Values[0] {
+				t.Fatalf("%s: write %d got %+v want %+v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package registers
+
+import "math"
+
+// 相数
+const (
+	SinglePhase = 1
+	ThreePhase  = 3
+)
+
+// 三相数据点键，对应 ThreePhaseData 的字段
+const (
+	KeyVoltageA       = "voltageA"
+	KeyVoltageB       = "voltageB"
+	KeyVoltageC       = "voltageC"
+	KeyCurrentA       = "currentA"
+	KeyCurrentB       = "currentB"
+	KeyCurrentC       = "currentC"
+	KeyActivePowerA   = "activePowerA"
+	KeyActivePowerB   = "activePowerB"
+	KeyActivePowerC   = "activePowerC"
+	KeyReactivePowerA = "reactivePowerA"
+	KeyReactivePowerB = "reactivePowerB"
+	KeyReactivePowerC = "reactivePowerC"
+	KeyApparentPowerA = "apparentPowerA"
+	KeyApparentPowerB = "apparentPowerB"
+	KeyApparentPowerC = "apparentPowerC"
+	KeyPowerFactorA   = "powerFactorA"
+	KeyPowerFactorB   = "powerFactorB"
+	KeyPowerFactorC   = "powerFactorC"
+
+	KeyVoltageAB = "voltageAB"
+	KeyVoltageBC = "voltageBC"
+	KeyVoltageCA = "voltageCA"
+
+	KeyTotalActivePower   = "totalActivePower"
+	KeyTotalReactivePower = "totalReactivePower"
+	KeyTotalApparentPower = "totalApparentPower"
+	KeyTotalPowerFactor   = "totalPowerFactor"
+
+	KeyImportEnergy = "importEnergy"
+	KeyExportEnergy = "exportEnergy"
+)
+
+// PhaseData 单相分量
+type PhaseData struct {
+	Voltage       float32 // 相电压 V
+	Current       float32 // 电流 A
+	ActivePower   float32 // 有功功率 W
+	ReactivePower float32 // 无功功率 VAR
+	ApparentPower float32 // 视在功率 VA
+	PowerFactor   float32 // 功率因数
+}
+
+// ThreePhaseData 三相电参量数据
+type ThreePhaseData struct {
+	PhaseA PhaseData
+	PhaseB PhaseData
+	PhaseC PhaseData
+
+	VoltageAB float32 // 线电压 V
+	VoltageBC float32
+	VoltageCA float32
+
+	TotalActivePower   float32 // 总有功功率 W
+	TotalReactivePower float32 // 总无功功率 VAR
+	TotalApparentPower float32 // 总视在功率 VA
+	TotalPowerFactor   float32 // 总功率因数
+	Frequency          float32 // 频率 Hz
+
+	ImportEnergy float32 // 正向有功总电能 kWh
+	ExportEnergy float32 // 反向有功总电能 kWh
+
+	Acquisition // 采集时间、各数据点质量、错误与往返耗时
+}
+
+// field 返回数据点键对应的字段指针，未知键返回 nil
+func (d *ThreePhaseData) field(key string) *float32 {
+	switch key {
+	case KeyVoltageA:
+		return &d.PhaseA.Voltage
+	case KeyVoltageB:
+		return &d.PhaseB.Voltage
+	case KeyVoltageC:
+		return &d.PhaseC.Voltage
+	case KeyCurrentA:
+		return &d.PhaseA.Current
+	case KeyCurrentB:
+		return &d.PhaseB.Current
+	case KeyCurrentC:
+		return &d.PhaseC.Current
+	case KeyActivePowerA:
+		return &d.PhaseA.ActivePower
+	case KeyActivePowerB:
+		return &d.PhaseB.ActivePower
+	case KeyActivePowerC:
+		return &d.PhaseC.ActivePower
+	case KeyReactivePowerA:
+		return &d.PhaseA.ReactivePower
+	case KeyReactivePowerB:
+		return &d.PhaseB.ReactivePower
+	case KeyReactivePowerC:
+		return &d.PhaseC.ReactivePower
+	case KeyApparentPowerA:
+		return &d.PhaseA.ApparentPower
+	case KeyApparentPowerB:
+		return &d.PhaseB.ApparentPower
+	case KeyApparentPowerC:
+		return &d.PhaseC.ApparentPower
+	case KeyPowerFactorA:
+		return &d.PhaseA.PowerFactor
+	case KeyPowerFactorB:
+		return &d.PhaseB.PowerFactor
+	case KeyPowerFactorC:
+		return &d.PhaseC.PowerFactor
+	case KeyVoltageAB:
+		return &d.VoltageAB
+	case KeyVoltageBC:
+		return &d.VoltageBC
+	case KeyVoltageCA:
+		return &d.VoltageCA
+	case KeyTotalActivePower:
+		return &d.TotalActivePower
+	case KeyTotalReactivePower:
+		return &d.TotalReactivePower
+	case KeyTotalApparentPower:
+		return &d.TotalApparentPower
+	case KeyTotalPowerFactor:
+		return &d.TotalPowerFactor
+	case KeyFrequency:
+		return &d.Frequency
+	case KeyImportEnergy:
+		return &d.ImportEnergy
+	case KeyExportEnergy:
+		return &d.ExportEnergy
+	}
+	return nil
+}
+
+// SetField 按数据点键设置字段，未知键返回 false
+func (d *ThreePhaseData) SetField(key string, value float32) bool {
+	f := d.field(key)
+	if f == nil {
+		return false
+	}
+	*f = value
+	return true
+}
+
+// Field 按数据点键读取字段
+func (d *ThreePhaseData) Field(key string) (float32, bool) {
+	f := d.field(key)
+	if f == nil {
+		return 0, false
+	}
+	return *f, true
+}
+
+// ParseThreePhaseData 按描述解析三相电参量，缺失或无法解析的数据点保持零值
+// 描述未提供视在功率时按 S = √(P² + Q²) 计算
+func (p *Profile) ParseThreePhaseData(regData map[uint16][]byte) *ThreePhaseData {
+	data := &ThreePhaseData{}
+	values := p.DecodeValues(regData)
+	for key, v := range values {
+		data.SetField(key, float32(v))
+	}
+
+	derive := func(s *float32, key string, active, reactive float32) {
+		if _, ok := values[key]; !ok {
+			*s = float32(math.Hypot(float64(active), float64(reactive)))
+		}
+	}
+	derive(&data.PhaseA.ApparentPower, KeyApparentPowerA, data.PhaseA.ActivePower, data.PhaseA.ReactivePower)
+	derive(&data.PhaseB.ApparentPower, KeyApparentPowerB, data.PhaseB.ActivePower, data.PhaseB.ReactivePower)
+	derive(&data.PhaseC.ApparentPower, KeyApparentPowerC, data.PhaseC.ActivePower, data.PhaseC.ReactivePower)
+	derive(&data.TotalApparentPower, KeyTotalApparentPower, data.TotalActivePower, data.TotalReactivePower)
+	return data
+}
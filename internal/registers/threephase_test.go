@@ -0,0 +1,50 @@
+package registers
+
+import "testing"
+
+func TestEmbeddedDTSU666Profile(t *testing.T) {
+	p, ok := GetProfile("DTSU666")
+	if !ok {
+		t.Fatalf("embedded profile DTSU666 not registered")
+	}
+	if p.Phases != ThreePhase || p.Parameters != "" {
+		t.Fatalf("unexpected profile attributes: phases=%d parameters=%q", p.Phases, p.Parameters)
+	}
+
+	// 每个数据点都能映射到三相数据模型
+	var data ThreePhaseData
+	for _, d := range p.Registers {
+		if !data.SetField(d.Key, 1) {
+			t.Fatalf("key %s has no ThreePhaseData field", d.Key)
+		}
+	}
+	if len(p.Group(GroupEnergy)) != 2 {
+		t.Fatalf("expected import/export energy in energy group")
+	}
+}
+
+func TestParseThreePhaseData(t *testing.T) {
+	p, _ := GetProfile("DTSU666")
+	regData := map[uint16][]byte{
+		0x2006: {0x45, 0x09, 0xD0, 0x00}, // 2205 × 0.1 = 220.5V
+		0x2044: {0x45, 0x9C, 0x40, 0x00}, // 5000 × 0.01 = 50Hz
+	}
+
+	data := p.ParseThreePhaseData(regData)
+	if !float32AlmostEqual(data.PhaseA.Voltage, 220.5, 0.001) || !float32AlmostEqual(data.Frequency, 50, 0.001) {
+		t.Fatalf("parsed values mismatch: %+v", data)
+	}
+	if data.PhaseB.Voltage != 0 || data.ImportEnergy != 0 {
+		t.Fatalf("missing registers should stay zero: %+v", data)
+	}
+
+	if _, ok := data.Field("unknown"); ok {
+		t.Fatalf("unknown key should not resolve")
+	}
+}
+
+func TestParseProfile_InvalidPhases(t *testing.T) {
+	if _, err := ParseProfile([]byte(`{"name": "X", "phases": 2, "registers": [{"key": "voltage", "address": 0}]}`)); err == nil {
+		t.Fatalf("expected error for invalid phases")
+	}
+}
@@ -2,10 +2,21 @@ package serial
 
 import (
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
 	"go.bug.st/serial"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/transport"
+)
+
+// 编译期检查 Connection 实现 transport.Transport、transport.FrameTimer 与 transport.LossDetector
+var (
+	_ transport.Transport    = (*Connection)(nil)
+	_ transport.FrameTimer   = (*Connection)(nil)
+	_ transport.LossDetector = (*Connection)(nil)
 )
 
 // Config 串口配置
@@ -17,12 +28,31 @@ type Config struct {
 	Parity   serial.Parity
 }
 
+// BitsPerChar 每个字符在线路上占用的位数（起始位+数据位+校验位+停止位）
+func (c Config) BitsPerChar() int {
+	bits := 1 + c.DataBits
+	if c.DataBits == 0 {
+		bits = 1 + 8
+	}
+	if c.Parity != serial.NoParity {
+		bits++
+	}
+	// 1.5 停止位按 2 位保守计算
+	if c.StopBits == serial.OneStopBit {
+		bits++
+	} else {
+		bits += 2
+	}
+	return bits
+}
+
 // Connection 串口连接
 type Connection struct {
 	port   serial.Port
 	config Config
 	mutex  sync.Mutex
 	isOpen bool
+	lost   bool // 读写出错后自行关闭，直到重新打开
 }
 
 // NewConnection 创建新的串口连接
@@ -56,6 +86,7 @@ func (c *Connection) Open() error {
 
 	c.port = port
 	c.isOpen = true
+	c.lost = false
 	return nil
 }
 
@@ -82,7 +113,11 @@ func (c *Connection) Write(data []byte) (int, error) {
 		return 0, fmt.Errorf("串口未打开")
 	}
 
-	return c.port.Write(data)
+	n, err := c.port.Write(data)
+	if err != nil {
+		return n, c.fail(err)
+	}
+	return n, nil
 }
 
 // Read 读取数据
@@ -94,7 +129,11 @@ func (c *Connection) Read(buffer []byte) (int, error) {
 		return 0, fmt.Errorf("串口未打开")
 	}
 
-	return c.port.Read(buffer)
+	n, err := c.port.Read(buffer)
+	if err != nil {
+		return n, c.fail(err)
+	}
+	return n, nil
 }
 
 // ReadWithTimeout 带超时的读取
@@ -106,8 +145,24 @@ func (c *Connection) ReadWithTimeout(buffer []byte, timeout time.Duration) (int,
 		return 0, fmt.Errorf("串口未打开")
 	}
 
-	c.port.SetReadTimeout(timeout)
-	return c.port.Read(buffer)
+	if err := c.port.SetReadTimeout(timeout); err != nil {
+		return 0, c.fail(err)
+	}
+	n, err := c.port.Read(buffer)
+	if err != nil {
+		return n, c.fail(err)
+	}
+	return n, nil
+}
+
+// fail 串口读写出错：驱动在读取超时时返回 (0, nil)，返回错误说明句柄已失效（多为 USB 适配器被拔出），
+// 关闭句柄并标记为断开，避免对失效句柄反复重试（调用方持有锁）
+func (c *Connection) fail(err error) error {
+	log.Printf("串口 %s 已断开: %v", c.config.Port, err)
+	c.port.Close()
+	c.isOpen = false
+	c.lost = true
+	return fmt.Errorf("串口 %s 已断开: %v", c.config.Port, err)
 }
 
 // IsOpen 检查串口是否打开
@@ -117,6 +172,23 @@ func (c *Connection) IsOpen() bool {
 	return c.isOpen
 }
 
+// Lost 串口是否因读写出错而断开
+func (c *Connection) Lost() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lost
+}
+
+// FrameTiming 返回由波特率与帧格式推算的 RTU 帧时序
+func (c *Connection) FrameTiming() modbus.RTUTiming {
+	return modbus.NewRTUTiming(c.config.BaudRate, c.config.BitsPerChar())
+}
+
+// Name 返回链路描述
+func (c *Connection) Name() string {
+	return fmt.Sprintf("串口 %s", c.config.Port)
+}
+
 // GetAvailablePorts 获取可用串口列表
 func GetAvailablePorts() ([]string, error) {
 	ports, err := serial.GetPortsList()
@@ -124,4 +196,4 @@ func GetAvailablePorts() ([]string, error) {
 		return nil, fmt.Errorf("获取串口列表失败: %v", err)
 	}
 	return ports, nil
-}
\ No newline at end of file
+}
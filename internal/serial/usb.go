@@ -0,0 +1,67 @@
+package serial
+
+import (
+	"fmt"
+	"strings"
+
+	"go.bug.st/serial/enumerator"
+)
+
+// USBIdentity USB 串口适配器的硬件标识
+// 适配器重新插入后系统可能分配新的端口名称（如 COM3 -> COM5、ttyUSB0 -> ttyUSB1），按该标识找回同一适配器
+type USBIdentity struct {
+	VID          string
+	PID          string
+	SerialNumber string // 部分廉价适配器（如 CH340）没有序列号，为空
+}
+
+// String 返回 "VID:PID" 形式的描述，有序列号时附加序列号
+func (id USBIdentity) String() string {
+	if id.SerialNumber == "" {
+		return fmt.Sprintf("%s:%s", id.VID, id.PID)
+	}
+	return fmt.Sprintf("%s:%s (%s)", id.VID, id.PID, id.SerialNumber)
+}
+
+// LookupUSB 返回端口所在 USB 适配器的标识，端口不存在或不是 USB 串口时 ok 为 false
+func LookupUSB(port string) (id USBIdentity, ok bool, err error) {
+	ports, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return USBIdentity{}, false, fmt.Errorf("获取串口列表失败: %v", err)
+	}
+	for _, p := range ports {
+		if p.Name == port && p.IsUSB {
+			return USBIdentity{VID: p.VID, PID: p.PID, SerialNumber: p.SerialNumber}, true, nil
+		}
+	}
+	return USBIdentity{}, false, nil
+}
+
+// FindUSB 查找具有指定标识的 USB 适配器当前的端口名称，未找到时 ok 为 false
+func FindUSB(id USBIdentity) (port string, ok bool, err error) {
+	ports, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return "", false, fmt.Errorf("获取串口列表失败: %v", err)
+	}
+	port, ok = matchUSB(ports, id)
+	return port, ok, nil
+}
+
+// matchUSB 在端口列表中查找标识匹配的 USB 适配器
+// 有序列号时按 VID/PID/序列号精确匹配；没有序列号时仅在 VID/PID 相同的适配器唯一时匹配，避免连到另一台同型号适配器
+func matchUSB(ports []*enumerator.PortDetails, id USBIdentity) (string, bool) {
+	var found []string
+	for _, p := range ports {
+		if !p.IsUSB || !strings.EqualFold(p.VID, id.VID) || !strings.EqualFold(p.PID, id.PID) {
+			continue
+		}
+		if id.SerialNumber != "" && p.SerialNumber != id.SerialNumber {
+			continue
+		}
+		found = append(found, p.Name)
+	}
+	if len(found) != 1 {
+		return "", false
+	}
+	return found[0], true
+}
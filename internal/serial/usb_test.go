@@ -0,0 +1,37 @@
+package serial
+
+import (
+	"testing"
+
+	"go.bug.st/serial/enumerator"
+)
+
+func TestMatchUSB(t *testing.T) {
+	ports := []*enumerator.PortDetails{
+		{Name: "COM1"},
+		{Name: "COM5", IsUSB: true, VID: "0403", PID: "6001", SerialNumber: "A10K3QX1"},
+		{Name: "COM6", IsUSB: true, VID: "0403", PID: "6001", SerialNumber: "A10K3QX2"},
+		{Name: "COM7", IsUSB: true, VID: "1A86", PID: "7523"},
+	}
+
+	tests := []struct {
+		name string
+		id   USBIdentity
+		want string
+		ok   bool
+	}{
+		{"序列号精确匹配", USBIdentity{VID: "0403", PID: "6001", SerialNumber: "A10K3QX2"}, "COM6", true},
+		{"VID/PID 不区分大小写", USBIdentity{VID: "1a86", PID: "7523"}, "COM7", true},
+		{"序列号不符", USBIdentity{VID: "0403", PID: "6001", SerialNumber: "FFFF"}, "", false},
+		{"无序列号且同型号不唯一", USBIdentity{VID: "0403", PID: "6001"}, "", false},
+		{"适配器不存在", USBIdentity{VID: "10C4", PID: "EA60"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := matchUSB(ports, tt.id)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("matchUSB(%v) = %q, %v, want %q, %v", tt.id, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// BusDevice 总线上一个从站的采集状态
+type BusDevice struct {
+	SlaveID      byte
+	Primary      bool      // 是否为主设备（SerialConfig.SlaveID）
+	Online       bool      // 最近一次轮询是否读到有效数据
+	Failures     int       // 连续失败次数
+	LastSeen     time.Time // 最近一次读到有效数据的时间
+	NextPoll     time.Time // 离线退避期间的下一次探测时间
+	ErrorMessage string    // 最近一次读取错误，读取恢复后清空
+}
+
+// GetBusDevices 返回总线上各从站的采集状态，主设备在前；未在采集时按配置列出且均为离线
+func (s *Service) GetBusDevices() []BusDevice {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	primary := byte(s.config.SlaveID)
+	if s.scheduler == nil || !s.scheduler.IsRunning() {
+		var devices []BusDevice
+		for _, id := range s.config.slaveIDs() {
+			devices = append(devices, BusDevice{SlaveID: id, Primary: id == primary})
+		}
+		return devices
+	}
+
+	health := s.scheduler.Health()
+	devices := make([]BusDevice, len(health))
+	for i, h := range health {
+		devices[i] = BusDevice{
+			SlaveID:      h.SlaveID,
+			Primary:      h.SlaveID == primary,
+			Online:       h.Online,
+			Failures:     h.Failures,
+			LastSeen:     h.LastSeen,
+			NextPoll:     h.NextPoll,
+			ErrorMessage: s.lastErrors[h.SlaveID],
+		}
+	}
+	return devices
+}
+
+// GetDeviceElectricalData 获取指定从站的最新电参量数据，尚无数据时返回 nil
+func (s *Service) GetDeviceElectricalData(slaveID byte) *ElectricalData {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.lastData[slaveID]
+}
+
+// GetDeviceThreePhaseData 获取指定从站的最新三相电参量数据，单相型号或尚无数据时返回 nil
+func (s *Service) GetDeviceThreePhaseData(slaveID byte) *ThreePhaseData {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.lastThree[slaveID]
+}
+
+// SetBusSlaveIDs 设置同一总线上需要一并轮询的其他从站地址（主设备地址仍由 SlaveID 指定）
+// 结果写入当前配置并同步到已保存的快照，采集进行中时立即生效
+func (s *Service) SetBusSlaveIDs(ids []int) error {
+	for _, id := range ids {
+		if id < 1 || id > 247 {
+			return fmt.Errorf("从站地址必须在 1-247 之间: %d", id)
+		}
+	}
+
+	s.mutex.Lock()
+	s.config.SlaveIDs = ids
+	s.mutex.Unlock()
+
+	saved, err := s.LoadSavedSerialConfig()
+	if err != nil {
+		return err
+	}
+	if saved != nil {
+		saved.SlaveIDs = ids
+		if err := s.SaveSavedSerialConfig(saved); err != nil {
+			return err
+		}
+	}
+	return s.restartIfRunning()
+}
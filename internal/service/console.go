@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/poller"
+)
+
+// consoleHistoryLimit 调试控制台保留的收发记录条数
+const consoleHistoryLimit = 100
+
+// maxConsoleFrame RTU 帧最大长度
+const maxConsoleFrame = 256
+
+// CRCVerdict 应答 CRC 校验结果
+type CRCVerdict string
+
+const (
+	CRCOK            CRCVerdict = "ok"
+	CRCBad           CRCVerdict = "bad"
+	CRCNotApplicable CRCVerdict = "n/a" // Modbus TCP 应答本身不含 CRC，链路还原 RTU 帧时由本机补齐，不作判定
+)
+
+// ConsoleTransaction 调试控制台的一次收发记录
+type ConsoleTransaction struct {
+	Time           time.Time
+	Request        []byte
+	Response       []byte
+	Duration       time.Duration // 发出请求到最后一个应答字节的耗时
+	CRC            CRCVerdict    // 应答 CRC 校验结果
+	Error          string        // 发送失败或无应答时的原因
+	RequestFields  []modbus.FrameField
+	ResponseFields []modbus.FrameField
+}
+
+// SendRawFrame 发送手工输入的十六进制帧并记录收发结果
+// appendCRC 为 true 时自动追加 CRC16；应答原样返回，CRC 错误或非 Modbus 格式的应答同样记录
+// Modbus TCP 链路发送时去掉帧末 CRC 并封装为 MBAP，因此要求帧带有正确的 CRC，应答 CRC 记为不适用
+// 输入非法或设备未连接时返回错误且不记录；无应答等通信失败记录在 ConsoleTransaction.Error 中
+func (s *Service) SendRawFrame(text string, appendCRC bool) (*ConsoleTransaction, error) {
+	frame, err := modbus.ParseHex(text)
+	if err != nil {
+		return nil, err
+	}
+	if appendCRC {
+		frame = modbus.AppendCRC(frame)
+	}
+	if len(frame) > maxConsoleFrame {
+		return nil, fmt.Errorf("帧长度超过 %d 字节: %d", maxConsoleFrame, len(frame))
+	}
+
+	s.mutex.RLock()
+	p := s.poller
+	tcp := s.config.transportType() == TransportModbusTCP
+	s.mutex.RUnlock()
+	if p == nil || !p.IsRunning() {
+		return nil, fmt.Errorf("设备未连接")
+	}
+	if tcp && !modbus.CheckCRC(frame) {
+		// 否则链路会把帧末两个数据字节当作 CRC 去掉
+		return nil, fmt.Errorf("Modbus TCP 链路须发送带正确 CRC 的完整 RTU 帧，请选择自动追加 CRC")
+	}
+
+	tx := ConsoleTransaction{
+		Time:          time.Now(),
+		Request:       frame,
+		RequestFields: modbus.DescribeRequest(frame),
+	}
+	response, elapsed, err := p.SendRaw(frame, poller.WriteTimeout)
+	tx.Duration = elapsed
+	if err != nil {
+		tx.Error = err.Error()
+	} else {
+		tx.Response = response
+		switch {
+		case tcp:
+			tx.CRC = CRCNotApplicable
+		case modbus.CheckCRC(response):
+			tx.CRC = CRCOK
+		default:
+			tx.CRC = CRCBad
+		}
+		tx.ResponseFields = modbus.DescribeResponse(response)
+	}
+
+	s.mutex.Lock()
+	s.console = append(s.console, tx)
+	if len(s.console) > consoleHistoryLimit {
+		s.console = s.console[len(s.console)-consoleHistoryLimit:]
+	}
+	s.mutex.Unlock()
+
+	return &tx, nil
+}
+
+// GetConsoleHistory 返回调试控制台收发记录，按时间先后排列
+func (s *Service) GetConsoleHistory() []ConsoleTransaction {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	history := make([]ConsoleTransaction, len(s.console))
+	copy(history, s.console)
+	return history
+}
+
+// ClearConsoleHistory 清空调试控制台收发记录
+func (s *Service) ClearConsoleHistory() {
+	s.mutex.Lock()
+	s.console = nil
+	s.mutex.Unlock()
+}
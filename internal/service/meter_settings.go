@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/registers"
+)
+
+// settingsClient 返回访问当前电表参数寄存器的客户端，设备型号须支持参数读写
+func (s *Service) settingsClient() (*modbus.Client, error) {
+	s.mutex.RLock()
+	name := s.config.profileName()
+	slaveID := byte(s.config.SlaveID)
+	s.mutex.RUnlock()
+
+	profile, ok := registers.GetProfile(name)
+	if !ok {
+		return nil, fmt.Errorf("未知的设备型号: %s", name)
+	}
+	if profile.Parameters != registers.ParametersChint {
+		return nil, fmt.Errorf("设备型号 %s 不支持参数读写", name)
+	}
+	return s.Client(slaveID)
+}
+
+// GetMeterSettings 读取电表参数寄存器
+func (s *Service) GetMeterSettings() (*registers.MeterSettings, error) {
+	c, err := s.settingsClient()
+	if err != nil {
+		return nil, err
+	}
+	regs, err := c.ReadHoldingRegisters(registers.SettingsStart, registers.SettingsQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("读取电表参数失败: %w", err)
+	}
+	return registers.ParseMeterSettings(regs)
+}
+
+// UpdateMeterSettings 修改电表通讯地址与波特率 (0x10)
+// 写入前读取当前参数并校验目标值；地址或波特率变化后按新参数更新配置（含已保存的快照）并自动重连
+func (s *Service) UpdateMeterSettings(address byte, baudRate int) error {
+	current, err := s.GetMeterSettings()
+	if err != nil {
+		return err
+	}
+	desired := *current
+	desired.Address = address
+	desired.BaudRate = baudRate
+
+	writes, err := registers.PlanSettingsWrites(current, &desired)
+	if err != nil {
+		return err
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+
+	c, err := s.settingsClient()
+	if err != nil {
+		return err
+	}
+	for _, w := range writes {
+		if err := c.WriteMultipleRegisters(w.Address, w.Values); err != nil {
+			return fmt.Errorf("写入参数寄存器 0x%04X 失败: %w", w.Address, err)
+		}
+		// 地址写入后电表只响应新地址
+		if w.Address == registers.RegAddress {
+			if c, err = s.Client(desired.Address); err != nil {
+				return err
+			}
+		}
+	}
+	log.Printf("电表参数已修改: 地址 0x%02X -> 0x%02X, 波特率 %d -> %d",
+		current.Address, desired.Address, current.BaudRate, desired.BaudRate)
+
+	return s.reconnectWith(int(desired.Address), desired.BaudRate)
+}
+
+// reconnectWith 以新的从站地址与波特率重建连接，并同步已保存的快照
+func (s *Service) reconnectWith(slaveID int, baudRate int) error {
+	cfg := s.GetSerialConfig()
+	cfg.SlaveID = slaveID
+	cfg.BaudRate = baudRate
+	if err := s.UpdateSerialConfig(cfg); err != nil {
+		return err
+	}
+
+	saved, err := s.LoadSavedSerialConfig()
+	if err != nil {
+		return err
+	}
+	if saved != nil {
+		saved.SlaveID = slaveID
+		saved.BaudRate = baudRate
+		if err := s.SaveSavedSerialConfig(saved); err != nil {
+			return err
+		}
+	}
+
+	return s.StartPolling()
+}
+
+// ClearEnergy 清零电表电能（写 CLrE 寄存器），操作不可恢复，调用方须先征得用户确认
+func (s *Service) ClearEnergy() error {
+	c, err := s.settingsClient()
+	if err != nil {
+		return err
+	}
+	if err := c.WriteMultipleRegisters(registers.RegClearEnergy, []uint16{registers.ClearEnergyCommand}); err != nil {
+		return fmt.Errorf("清零电能失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"DDSUViewer/internal/serial"
+)
+
+// 自动重连退避参数：第 1 次在断开 minReconnectDelay 后尝试，此后间隔加倍，上限 maxReconnectDelay
+const (
+	minReconnectDelay = 1 * time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// reconnectDelay 第 attempt 次重连前的等待时间
+func reconnectDelay(attempt int) time.Duration {
+	if attempt > 6 {
+		return maxReconnectDelay
+	}
+	if d := minReconnectDelay << (attempt - 1); d < maxReconnectDelay {
+		return d
+	}
+	return maxReconnectDelay
+}
+
+// beginReconnect 链路意外断开：停止调度、释放链路并在后台重连（调用方持有锁）
+func (s *Service) beginReconnect() {
+	log.Printf("%s 已断开，开始自动重连", s.conn.Name())
+	s.scheduler.Stop()
+	s.scheduler = nil
+	s.poller = nil
+	s.conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.reconnect = cancel
+	s.status.ReconnectAttempt = 0
+	s.status.ErrorMessage = fmt.Sprintf("%s 已断开，等待重连", s.conn.Name())
+	s.setState(StateReconnecting)
+	go s.reconnectLoop(ctx)
+}
+
+// cancelReconnect 放弃进行中的自动重连（调用方持有锁）
+func (s *Service) cancelReconnect() {
+	if s.reconnect != nil {
+		s.reconnect()
+		s.reconnect = nil
+	}
+}
+
+// reconnectLoop 按退避间隔反复按当前配置重新打开链路，直到成功或被 StopPolling 等取消
+func (s *Service) reconnectLoop(ctx context.Context) {
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay(attempt)):
+		}
+
+		s.mutex.Lock()
+		if ctx.Err() != nil {
+			s.mutex.Unlock()
+			return
+		}
+		s.status.ReconnectAttempt = attempt
+		s.status.ErrorMessage = fmt.Sprintf("重连中，第 %d 次", attempt)
+		s.broadcastStatus()
+
+		s.resolvePort()
+		err := s.start()
+		if err == nil {
+			log.Printf("第 %d 次重连成功: %s", attempt, s.conn.Name())
+			s.reconnect = nil
+			s.mutex.Unlock()
+			return
+		}
+		log.Printf("第 %d 次重连失败: %v", attempt, err)
+		s.status.ErrorMessage = fmt.Sprintf("重连中，第 %d 次失败: %v", attempt, err)
+		s.broadcastStatus()
+		s.mutex.Unlock()
+	}
+}
+
+// identifyUSB 记录当前串口所在 USB 适配器的标识，已记录或非串口链路时跳过（调用方持有锁）
+func (s *Service) identifyUSB() {
+	if s.usb != nil || s.config.transportType() != TransportSerial {
+		return
+	}
+	id, ok, err := serial.LookupUSB(s.config.Port)
+	if err != nil {
+		log.Printf("识别 USB 串口适配器失败: %v", err)
+		return
+	}
+	if ok {
+		s.usb = &id
+	}
+}
+
+// resolvePort 重连前确定串口：原端口仍存在时沿用，否则按 USB 标识查找改名后的同一适配器（调用方持有锁）
+// 找到时只修改当前配置，不改写已保存的快照
+func (s *Service) resolvePort() {
+	if s.usb == nil || s.config.transportType() != TransportSerial {
+		return
+	}
+	ports, err := serial.GetAvailablePorts()
+	if err != nil {
+		return
+	}
+	for _, p := range ports {
+		if p == s.config.Port {
+			return
+		}
+	}
+	port, ok, err := serial.FindUSB(*s.usb)
+	if err != nil || !ok {
+		return
+	}
+	log.Printf("USB 串口适配器 %s 的端口由 %s 变为 %s", s.usb, s.config.Port, port)
+	s.config.Port = port
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"DDSUViewer/internal/simulator"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	want := []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second, 30 * time.Second}
+	for i, w := range want {
+		if got := reconnectDelay(i + 1); got != w {
+			t.Errorf("reconnectDelay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+// waitState 等待状态通道上出现指定状态，返回该状态
+func waitState(t *testing.T, ch <-chan *DeviceStatus, want ConnectionState, timeout time.Duration) *DeviceStatus {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case st := <-ch:
+			if st.State == want {
+				return st
+			}
+		case <-deadline:
+			t.Fatalf("timed out waiting for state %s", want)
+			return nil
+		}
+	}
+}
+
+func TestReconnect_Simulator(t *testing.T) {
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator})
+	statusCh := s.SubscribeStatus("test")
+	defer s.Unsubscribe("test")
+
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+	waitState(t, statusCh, StateOnline, 3*time.Second)
+
+	// 模拟拔出适配器
+	s.mutex.RLock()
+	lost := s.conn.(*simulator.MemoryTransport)
+	s.mutex.RUnlock()
+	lost.Disconnect()
+
+	waitState(t, statusCh, StateReconnecting, 3*time.Second)
+	waitState(t, statusCh, StateOnline, 5*time.Second)
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.conn == lost {
+		t.Fatalf("expected a new transport after reconnect")
+	}
+	if s.status.ReconnectAttempt != 0 || s.reconnect != nil {
+		t.Fatalf("reconnect state not cleared: attempt=%d", s.status.ReconnectAttempt)
+	}
+}
+
+func TestStopPolling_CancelsReconnect(t *testing.T) {
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator})
+	statusCh := s.SubscribeStatus("test")
+	defer s.Unsubscribe("test")
+
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	waitState(t, statusCh, StateOnline, 3*time.Second)
+
+	s.mutex.RLock()
+	s.conn.(*simulator.MemoryTransport).Disconnect()
+	s.mutex.RUnlock()
+	waitState(t, statusCh, StateReconnecting, 3*time.Second)
+
+	s.StopPolling()
+	time.Sleep(reconnectDelay(1) + 200*time.Millisecond)
+	if st := s.GetDeviceStatus(); st.State != StateDisconnected || st.ReconnectAttempt != 0 {
+		t.Fatalf("expected disconnected without reconnect, got %s (attempt %d)", st.State, st.ReconnectAttempt)
+	}
+}
+
+func TestReconnect_NoGoroutineLeak(t *testing.T) {
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator})
+	statusCh := s.SubscribeStatus("test")
+	defer s.Unsubscribe("test")
+
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+	waitState(t, statusCh, StateOnline, 3*time.Second)
+	baseline := runtime.NumGoroutine()
+
+	// 适配器反复拔插，每次重连后的协程数应保持不变
+	for i := 0; i < 3; i++ {
+		s.mutex.RLock()
+		s.conn.(*simulator.MemoryTransport).Disconnect()
+		s.mutex.RUnlock()
+		waitState(t, statusCh, StateReconnecting, 3*time.Second)
+		waitState(t, statusCh, StateOnline, 5*time.Second)
+	}
+	if n := settledGoroutines(baseline); n > baseline {
+		t.Fatalf("goroutines grew across reconnects: %d -> %d", baseline, n)
+	}
+}
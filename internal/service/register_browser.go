@@ -0,0 +1,42 @@
+package service
+
+import (
+	"fmt"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/registers"
+)
+
+// ReadRawRegisters 读取任意地址区间的原始寄存器 (0x03/0x04) 并给出多种解读
+// 请求经轮询器的串口互斥锁执行，不会打断正在进行的采集
+func (s *Service) ReadRawRegisters(function byte, start uint16, quantity uint16) (*registers.RawReading, error) {
+	if function != modbus.FunctionReadHoldingRegisters && function != modbus.FunctionReadInputRegisters {
+		return nil, fmt.Errorf("不支持的读功能码: 0x%02X", function)
+	}
+	if quantity == 0 || quantity > modbus.MaxReadRegisters {
+		return nil, fmt.Errorf("读取数量必须在 1-%d 之间: %d", modbus.MaxReadRegisters, quantity)
+	}
+	if int(start)+int(quantity) > 0x10000 {
+		return nil, fmt.Errorf("地址区间越界: 0x%04X + %d", start, quantity)
+	}
+
+	s.mutex.RLock()
+	slaveID := byte(s.config.SlaveID)
+	s.mutex.RUnlock()
+
+	c, err := s.Client(slaveID)
+	if err != nil {
+		return nil, err
+	}
+
+	var words []uint16
+	if function == modbus.FunctionReadInputRegisters {
+		words, err = c.ReadInputRegisters(start, quantity)
+	} else {
+		words, err = c.ReadHoldingRegisters(start, quantity)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取寄存器 0x%04X 失败: %w", start, err)
+	}
+	return registers.InterpretWords(function, start, words), nil
+}
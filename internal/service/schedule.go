@@ -0,0 +1,48 @@
+package service
+
+import (
+	"DDSUViewer/internal/poller"
+)
+
+// GetPollSchedule 返回当前型号各分组的轮询间隔（含未单独设置、使用默认值的分组）
+func (s *Service) GetPollSchedule() poller.Schedule {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	schedule := s.config.schedule().Clone()
+	if profile, err := s.activeProfile(); err == nil {
+		for _, group := range profile.Groups() {
+			schedule[group] = schedule.Interval(group)
+		}
+	}
+	return schedule
+}
+
+// SetPollSchedule 设置各分组的轮询间隔，未列出的分组保持原值
+// 采集进行中时从下一个节拍起生效，无需重启；结果同步到已保存的快照
+func (s *Service) SetPollSchedule(schedule poller.Schedule) error {
+	s.mutex.Lock()
+	merged := s.config.schedule().Clone()
+	for group, d := range schedule {
+		merged[group] = d
+	}
+	if err := merged.Validate(); err != nil {
+		s.mutex.Unlock()
+		return err
+	}
+	s.config.Schedule = merged
+	if s.scheduler != nil {
+		s.scheduler.SetSchedule(merged)
+	}
+	s.mutex.Unlock()
+
+	saved, err := s.LoadSavedSerialConfig()
+	if err != nil {
+		return err
+	}
+	if saved != nil {
+		saved.Schedule = merged
+		return s.SaveSavedSerialConfig(saved)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -11,42 +12,126 @@ import (
 
 	goserial "go.bug.st/serial"
 
+	"DDSUViewer/internal/modbus"
 	"DDSUViewer/internal/poller"
+	"DDSUViewer/internal/registers"
 	"DDSUViewer/internal/serial"
+	"DDSUViewer/internal/simulator"
+	"DDSUViewer/internal/transport"
 )
 
 // Service 服务管理器
 type Service struct {
-	conn         *serial.Connection
-	poller       *poller.Poller
-	config       *SerialConfig
-	status       *DeviceStatus
-	lastData     *ElectricalData
-	mutex        sync.RWMutex
-	subscribers  map[string]chan *ElectricalData
-	statusSubs   map[string]chan *DeviceStatus
+	conn        transport.Transport
+	scheduler   *poller.Scheduler // 总线调度器，轮流采集总线上的全部从站
+	poller      *poller.Poller    // 主设备（SerialConfig.SlaveID）的轮询器
+	config      *SerialConfig
+	status      *DeviceStatus // 主设备与链路的状态
+	lastData    map[byte]*ElectricalData
+	lastThree   map[byte]*ThreePhaseData
+	lastErrors  map[byte]string // 从站地址 -> 最近一次读取错误，读取恢复后清除
+	mutex       sync.RWMutex
+	subscribers map[string]chan *ElectricalData
+	statusSubs  map[string]chan *DeviceStatus
+	console     []ConsoleTransaction // 调试控制台收发记录，最多 consoleHistoryLimit 条
+	usb         *serial.USBIdentity  // 当前串口所在 USB 适配器的标识，重连时据此找回改名的端口
+	reconnect   context.CancelFunc   // 进行中的自动重连，nil 表示未在重连
 }
 
+// 链路类型
+const (
+	TransportSerial     = "serial"  // 本地串口 Modbus RTU
+	TransportModbusTCP  = "tcp"     // 以太网网关 Modbus TCP
+	TransportRTUOverTCP = "rtu-tcp" // 串口服务器透传 RTU 帧
+	TransportSimulator  = "sim"     // 内置 DDSU666 仿真电表，无需硬件
+)
+
 // SerialConfig 串口配置
 type SerialConfig struct {
-	Port     string
-	BaudRate int
-	DataBits int
-	StopBits goserial.StopBits
-	Parity   goserial.Parity
-	SlaveID  int
+	Port       string
+	BaudRate   int
+	DataBits   int
+	StopBits   goserial.StopBits
+	Parity     goserial.Parity
+	SlaveID    int
+	SlaveIDs   []int           // 同一总线上需要一并轮询的其他从站地址，SlaveID 为主设备
+	Transport  string          // 链路类型，空值视为 TransportSerial
+	Host       string          // TCP 主机地址
+	TCPPort    int             // TCP 端口
+	Profile    string          // 设备型号（设备描述名称），空值视为 registers.DefaultProfile；三相型号（如 DTSU666）的数据经 GetThreePhaseData 获取
+	ByteOrder  string          // 端序校准结果，空值表示沿用设备描述中的字节序
+	Schedule   poller.Schedule // 各分组轮询间隔，nil 使用 poller.DefaultSchedule
+	Thresholds StateThresholds // 连接状态切换阈值，零值使用默认值
+}
+
+// clone 返回配置的深拷贝，切片与映射不与原配置共享
+func (c *SerialConfig) clone() *SerialConfig {
+	cp := *c
+	if c.SlaveIDs != nil {
+		cp.SlaveIDs = append([]int(nil), c.SlaveIDs...)
+	}
+	if c.Schedule != nil {
+		cp.Schedule = c.Schedule.Clone()
+	}
+	return &cp
+}
+
+// transportType 返回规范化的链路类型
+func (c *SerialConfig) transportType() string {
+	if c.Transport == "" {
+		return TransportSerial
+	}
+	return c.Transport
+}
+
+// profileName 返回规范化的设备型号
+func (c *SerialConfig) profileName() string {
+	if c.Profile == "" {
+		return registers.DefaultProfile
+	}
+	return c.Profile
+}
+
+// schedule 返回规范化的轮询计划
+func (c *SerialConfig) schedule() poller.Schedule {
+	if c.Schedule == nil {
+		return poller.DefaultSchedule()
+	}
+	return c.Schedule
+}
+
+// slaveIDs 返回总线上需要轮询的全部从站地址，主设备在前，重复地址只保留一次
+func (c *SerialConfig) slaveIDs() []byte {
+	ids := []byte{byte(c.SlaveID)}
+	seen := map[byte]bool{byte(c.SlaveID): true}
+	for _, id := range c.SlaveIDs {
+		if !seen[byte(id)] {
+			seen[byte(id)] = true
+			ids = append(ids, byte(id))
+		}
+	}
+	return ids
 }
 
 // DeviceStatus 设备状态
 type DeviceStatus struct {
-	Connected    bool
-	Protocol     string
-	LastUpdate   time.Time
-	ErrorMessage string
+	State            ConnectionState // 连接状态，由主设备连续成功/失败次数驱动
+	Since            time.Time       // 进入当前状态的时间
+	Failures         int             // 主设备连续失败次数
+	ReconnectAttempt int             // 自动重连的尝试次数，仅在 Reconnecting 状态下非 0
+	Protocol         string
+	LastUpdate       time.Time
+	ErrorMessage     string
+}
+
+// Connected 电表是否有应答（Online 或 Degraded）
+func (st *DeviceStatus) Connected() bool {
+	return st.State == StateOnline || st.State == StateDegraded
 }
 
 // ElectricalData 电参量数据
 type ElectricalData struct {
+	SlaveID       byte // 数据来源从站地址，总线上有多台电表时用于区分设备
 	Voltage       float64
 	Current       float64
 	ActivePower   float64
@@ -55,50 +140,137 @@ type ElectricalData struct {
 	PowerFactor   float64
 	Frequency     float64
 	ActiveEnergy  float64
-	Timestamp     time.Time
+
+	// 分向电能，仅当设备描述定义了对应数据点时有效
+	ForwardActiveEnergy   float64
+	ReverseActiveEnergy   float64
+	ForwardReactiveEnergy float64
+	ReverseReactiveEnergy float64
+
+	Sample
+}
+
+// Sample 一次轮询结果的采集信息
+type Sample struct {
+	Timestamp time.Time                    // 采集时间（读取完成时刻）
+	Latency   time.Duration                // 本次成功读取事务的往返耗时之和
+	Quality   map[string]registers.Quality // 数据点键 -> 质量
+	Error     string                       // 本次读取中的第一个错误，全部成功时为空
+	Stale     bool                         // 存在沿用上次值、无效或读取失败的数据点
+}
+
+// newSample 转换轮询器的采集信息
+func newSample(a registers.Acquisition) Sample {
+	sample := Sample{Timestamp: a.Timestamp, Latency: a.Latency, Quality: a.Quality, Stale: a.Stale()}
+	if a.Err != nil {
+		sample.Error = a.Err.Error()
+	}
+	return sample
+}
+
+// 用户自定义设备描述目录（相对于应用工作目录）
+const profileDir = "data/profiles"
+
+// PhaseData 单相分量
+type PhaseData struct {
+	Voltage       float64
+	Current       float64
+	ActivePower   float64
+	ReactivePower float64
+	ApparentPower float64
+	PowerFactor   float64
+}
+
+// ThreePhaseData 三相电参量数据
+type ThreePhaseData struct {
+	SlaveID            byte // 数据来源从站地址
+	PhaseA             PhaseData
+	PhaseB             PhaseData
+	PhaseC             PhaseData
+	VoltageAB          float64
+	VoltageBC          float64
+	VoltageCA          float64
+	TotalActivePower   float64
+	TotalReactivePower float64
+	TotalApparentPower float64
+	TotalPowerFactor   float64
+	Frequency          float64
+	ImportEnergy       float64
+	ExportEnergy       float64
+	Sample
 }
 
 // NewService 创建服务实例
 func NewService() *Service {
-	return &Service{
+	// 加载用户自定义设备描述，同名描述覆盖内置描述
+	if err := registers.LoadProfileDir(profileDir); err != nil {
+		log.Printf("加载设备描述失败: %v", err)
+	}
+
+	s := &Service{
 		config: &SerialConfig{
-			Port:     "", // 用户选择端口
-			BaudRate: 9600,
-			DataBits: 8,
-			StopBits: goserial.OneStopBit,
-			Parity:   goserial.NoParity,
-			SlaveID:  0, // 未设置，需要用户配置
+			Port:      "", // 用户选择端口
+			BaudRate:  9600,
+			DataBits:  8,
+			StopBits:  goserial.OneStopBit,
+			Parity:    goserial.NoParity,
+			SlaveID:   0, // 未设置，需要用户配置
+			Transport: TransportSerial,
+			TCPPort:   modbus.DefaultTCPPort,
 		},
 		status: &DeviceStatus{
-			Connected:  false,
+			State:      StateDisconnected,
+			Since:      time.Now(),
 			Protocol:   "Modbus RTU",
 			LastUpdate: time.Now(),
 		},
+		lastData:    make(map[byte]*ElectricalData),
+		lastThree:   make(map[byte]*ThreePhaseData),
+		lastErrors:  make(map[byte]string),
 		subscribers: make(map[string]chan *ElectricalData),
 		statusSubs:  make(map[string]chan *DeviceStatus),
 	}
+
+	// 恢复已保存的快照：端序校准、轮询间隔、总线从站与状态阈值等单独设置的项只保存在快照中，
+	// 须在启动时载入当前配置，否则采集按默认值进行，且下次保存配置时会被默认值覆盖
+	saved, err := s.LoadSavedSerialConfig()
+	if err != nil {
+		log.Printf("加载串口快照失败: %v", err)
+	} else if saved != nil {
+		s.config = saved
+	}
+	return s
 }
 
-// GetElectricalData 获取最新电参量数据
+// GetElectricalData 获取主设备最新电参量数据，其他从站见 GetDeviceElectricalData
 func (s *Service) GetElectricalData() *ElectricalData {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	
-	return s.lastData // 如果没有数据就返回 nil
+
+	return s.lastData[byte(s.config.SlaveID)] // 如果没有数据就返回 nil
+}
+
+// GetThreePhaseData 获取主设备最新三相电参量数据，单相型号或尚无数据时返回 nil
+func (s *Service) GetThreePhaseData() *ThreePhaseData {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.lastThree[byte(s.config.SlaveID)]
 }
 
-// GetDeviceStatus 获取设备状态
+// GetDeviceStatus 获取设备状态的副本
 func (s *Service) GetDeviceStatus() *DeviceStatus {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	return s.status
+	status := *s.status
+	return &status
 }
 
-// GetSerialConfig 获取串口配置
+// GetSerialConfig 获取串口配置的副本，调用方可自由读写而不影响采集
 func (s *Service) GetSerialConfig() *SerialConfig {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
-	return s.config
+	return s.config.clone()
 }
 
 // UpdateSerialConfig 更新串口配置
@@ -106,15 +278,17 @@ func (s *Service) UpdateSerialConfig(config *SerialConfig) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	// 如果正在运行，先停止
-	if s.poller != nil && s.poller.IsRunning() {
-		s.poller.Stop()
+	// 如果正在运行或重连，先停止
+	s.cancelReconnect()
+	if s.scheduler != nil && s.scheduler.IsRunning() {
+		s.scheduler.Stop()
 	}
 	if s.conn != nil && s.conn.IsOpen() {
 		s.conn.Close()
 	}
+	s.setState(StateDisconnected)
 
-	s.config = config
+	s.config = config.clone()
 	return nil
 }
 
@@ -123,83 +297,189 @@ func (s *Service) StartPolling() error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if s.poller != nil && s.poller.IsRunning() {
+	if s.scheduler != nil && s.scheduler.IsRunning() {
 		return nil // 已在运行
 	}
 
-	// 检查串口配置
-	if s.config.Port == "" {
-		err := fmt.Errorf("请选择串口")
-		s.status.Connected = false
-		s.status.ErrorMessage = err.Error()
+	// 手动启动时放弃进行中的自动重连，重新识别 USB 适配器
+	s.cancelReconnect()
+	s.usb = nil
+	if err := s.start(); err != nil {
+		return s.failStart(err)
+	}
+	return nil
+}
+
+// start 按当前配置打开链路并启动调度，成功后进入 Connecting 状态（调用方持有锁）
+// 失败时不改变状态，由调用方决定进入 Error 还是继续重连
+func (s *Service) start() error {
+	// 检查链路配置
+	if err := s.validateTransportConfig(); err != nil {
 		return err
 	}
-	
+
 	// 检查从站地址
 	if s.config.SlaveID == 0 {
-		err := fmt.Errorf("请设置从站地址")
-		s.status.Connected = false
-		s.status.ErrorMessage = err.Error()
+		return fmt.Errorf("请设置从站地址")
+	}
+
+	// 检查轮询计划（可能来自手工修改的快照）
+	if err := s.config.schedule().Validate(); err != nil {
 		return err
 	}
-	
-	log.Printf("使用配置: 端口=%s, 从站地址=0x%02X", s.config.Port, s.config.SlaveID)
 
-	// 创建串口连接
-	serialConfig := serial.Config{
-		Port:     s.config.Port,
-		BaudRate: s.config.BaudRate,
-		DataBits: s.config.DataBits,
-		StopBits: s.config.StopBits,
-		Parity:   s.config.Parity,
+	// 查找设备描述并应用端序校准结果
+	profile, err := s.activeProfile()
+	if err != nil {
+		return err
+	}
+
+	// 创建通信链路
+	conn, err := s.newTransport()
+	if err != nil {
+		return err
 	}
+	s.conn = conn
+	log.Printf("使用配置: 链路=%s, 从站地址=0x%02X", s.conn.Name(), s.config.SlaveID)
 
-	s.conn = serial.NewConnection(serialConfig)
 	if err := s.conn.Open(); err != nil {
-		s.status.Connected = false
 		// 提供更详细的错误信息
-		if strings.Contains(err.Error(), "not found") {
-			s.status.ErrorMessage = fmt.Sprintf("串口 %s 不存在，请检查设备连接", s.config.Port)
+		if s.config.transportType() != TransportSerial {
+			return err
+		} else if strings.Contains(err.Error(), "not found") {
+			return fmt.Errorf("串口 %s 不存在，请检查设备连接", s.config.Port)
 		} else if strings.Contains(err.Error(), "Access is denied") || strings.Contains(err.Error(), "busy") {
-			s.status.ErrorMessage = fmt.Sprintf("串口 %s 被占用，请关闭其他程序后重试", s.config.Port)
-		} else {
-			s.status.ErrorMessage = fmt.Sprintf("打开串口失败: %v", err)
+			return fmt.Errorf("串口 %s 被占用，请关闭其他程序后重试", s.config.Port)
 		}
-		return fmt.Errorf(s.status.ErrorMessage)
+		return fmt.Errorf("打开串口失败: %v", err)
 	}
-
-	// 创建轮询器
-	s.poller = poller.NewPoller(s.conn, byte(s.config.SlaveID))
-	if err := s.poller.Start(); err != nil {
+	s.identifyUSB()
+
+	// 为总线上的每个从站创建轮询器，由调度器轮流采集
+	bus := poller.NewBus(s.conn)
+	scheduler := poller.NewScheduler(bus, s.config.schedule())
+	scheduler.SetOfflineAfter(s.config.Thresholds.normalized().OfflineAfter)
+	for _, id := range s.config.slaveIDs() {
+		p := poller.NewBusPoller(bus, id)
+		p.SetProfile(profile)
+		if err := scheduler.Add(p); err != nil {
+			s.conn.Close()
+			return err
+		}
+	}
+	if err := scheduler.Start(); err != nil {
 		s.conn.Close()
 		return err
 	}
+	s.scheduler = scheduler
+	s.poller = scheduler.Pollers()[0]
 
-	s.status.Connected = true
+	// 链路已打开，电表应答后由 listenHealth 切换为 Online
+	s.status.Protocol = protocolName(s.config.transportType())
 	s.status.ErrorMessage = ""
-	
-	// 通知状态订阅者
-	for _, ch := range s.statusSubs {
-		select {
-		case ch <- s.status:
-		default:
-			// 通道满时跳过
-		}
+	s.status.Failures = 0
+	s.status.ReconnectAttempt = 0
+	s.setState(StateConnecting)
+
+	// 启动数据、错误与状态监听，调度停止后随之退出
+	go s.listenHealth(scheduler)
+	for _, p := range scheduler.Pollers() {
+		go s.listenData(scheduler, p)
+		go s.listenThreePhase(scheduler, p)
+		go s.listenErrors(scheduler, p)
 	}
 
-	// 启动数据监听
-	go s.listenData()
+	return nil
+}
+
+// activeProfile 返回应用了端序校准结果的设备描述（调用方需持有锁）
+func (s *Service) activeProfile() (*registers.Profile, error) {
+	profile, ok := registers.GetProfile(s.config.profileName())
+	if !ok {
+		return nil, fmt.Errorf("未知的设备型号: %s", s.config.profileName())
+	}
+	if s.config.ByteOrder == "" {
+		return profile, nil
+	}
+	return profile.WithByteOrder(s.config.ByteOrder)
+}
 
+// validateTransportConfig 检查当前链路类型所需的配置项
+func (s *Service) validateTransportConfig() error {
+	switch s.config.transportType() {
+	case TransportSerial:
+		if s.config.Port == "" {
+			return fmt.Errorf("请选择串口")
+		}
+	case TransportModbusTCP, TransportRTUOverTCP:
+		if s.config.Host == "" {
+			return fmt.Errorf("请设置网关地址")
+		}
+	case TransportSimulator:
+	default:
+		return fmt.Errorf("不支持的链路类型: %s", s.config.Transport)
+	}
 	return nil
 }
 
+// simulatedResponseDelay 仿真电表的应答延时，接近 9600bps 下真实电表的响应时间
+const simulatedResponseDelay = 20 * time.Millisecond
+
+// newTransport 按当前配置创建通信链路
+func (s *Service) newTransport() (transport.Transport, error) {
+	switch s.config.transportType() {
+	case TransportSerial:
+		serialConfig := serial.Config{
+			Port:     s.config.Port,
+			BaudRate: s.config.BaudRate,
+			DataBits: s.config.DataBits,
+			StopBits: s.config.StopBits,
+			Parity:   s.config.Parity,
+		}
+		return serial.NewConnection(serialConfig), nil
+	case TransportModbusTCP:
+		return transport.NewModbusTCPClient(s.config.Host, s.config.TCPPort), nil
+	case TransportRTUOverTCP:
+		return transport.NewRTUOverTCPConnection(s.config.Host, s.config.TCPPort), nil
+	case TransportSimulator:
+		// 为总线上的每个从站各仿真一台电表
+		var meters []*simulator.Meter
+		for i, id := range s.config.slaveIDs() {
+			meters = append(meters, simulator.NewMeter(simulator.Config{
+				SlaveID:  id,
+				Waveform: simulator.DefaultWaveform(),
+				Seed:     time.Now().UnixNano() + int64(i),
+			}))
+		}
+		return simulator.NewBusTransport(meters, simulatedResponseDelay), nil
+	default:
+		return nil, fmt.Errorf("不支持的链路类型: %s", s.config.Transport)
+	}
+}
+
+// protocolName 返回链路类型对应的协议名称
+func protocolName(transportType string) string {
+	switch transportType {
+	case TransportModbusTCP:
+		return "Modbus TCP"
+	case TransportRTUOverTCP:
+		return "Modbus RTU over TCP"
+	case TransportSimulator:
+		return "Modbus RTU (仿真)"
+	default:
+		return "Modbus RTU"
+	}
+}
+
 // StopPolling 停止数据采集
 func (s *Service) StopPolling() error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if s.poller != nil {
-		s.poller.Stop()
+	s.cancelReconnect()
+	if s.scheduler != nil {
+		s.scheduler.Stop()
+		s.scheduler = nil
 		s.poller = nil
 	}
 	if s.conn != nil {
@@ -207,19 +487,144 @@ func (s *Service) StopPolling() error {
 		s.conn = nil
 	}
 
-	s.status.Connected = false
 	s.status.ErrorMessage = ""
-	
-	// 通知状态订阅者
-	for _, ch := range s.statusSubs {
-		select {
-		case ch <- s.status:
-		default:
-			// 通道满时跳过
+	s.status.Failures = 0
+	s.status.ReconnectAttempt = 0
+	s.setState(StateDisconnected)
+
+	return nil
+}
+
+// WriteRegisters 在轮询间隙写入寄存器：单个寄存器使用 0x06，多个使用 0x10
+func (s *Service) WriteRegisters(startAddr uint16, values []uint16) error {
+	s.mutex.RLock()
+	p := s.poller
+	s.mutex.RUnlock()
+
+	if p == nil || !p.IsRunning() {
+		return fmt.Errorf("设备未连接")
+	}
+	if len(values) == 1 {
+		return p.WriteRegister(startAddr, values[0])
+	}
+	return p.WriteRegisters(startAddr, values)
+}
+
+// Client 返回共享当前连接的 Modbus 客户端，可访问任意从站地址与功能码
+func (s *Service) Client(slaveID byte) (*modbus.Client, error) {
+	s.mutex.RLock()
+	p := s.poller
+	s.mutex.RUnlock()
+
+	if p == nil || !p.IsRunning() {
+		return nil, fmt.Errorf("设备未连接")
+	}
+	return modbus.NewClient(p, slaveID), nil
+}
+
+// GetProfiles 返回可用的设备型号
+func (s *Service) GetProfiles() []string {
+	return registers.ProfileNames()
+}
+
+// GetActiveProfile 返回当前配置的设备描述（已应用端序校准结果），采集与界面字段映射均以此为准
+func (s *Service) GetActiveProfile() (*registers.Profile, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.activeProfile()
+}
+
+// SetProfile 切换设备型号，结果同步到已保存的快照；采集进行中时自动按新描述重启采集
+func (s *Service) SetProfile(name string) error {
+	if _, ok := registers.GetProfile(name); !ok {
+		return fmt.Errorf("未知的设备型号: %s", name)
+	}
+
+	s.mutex.Lock()
+	if s.config.profileName() != name {
+		// 端序校准结果与已采集数据只对原型号有效
+		s.config.ByteOrder = ""
+		s.lastData = make(map[byte]*ElectricalData)
+		s.lastThree = make(map[byte]*ThreePhaseData)
+	}
+	s.config.Profile = name
+	s.mutex.Unlock()
+
+	saved, err := s.LoadSavedSerialConfig()
+	if err != nil {
+		return err
+	}
+	if saved != nil {
+		if saved.profileName() != name {
+			saved.ByteOrder = ""
+		}
+		saved.Profile = name
+		if err := s.SaveSavedSerialConfig(saved); err != nil {
+			return err
 		}
 	}
-	
-	return nil
+	return s.restartIfRunning()
+}
+
+// restartIfRunning 采集进行中时按当前配置重启采集
+func (s *Service) restartIfRunning() error {
+	s.mutex.RLock()
+	running := s.scheduler != nil && s.scheduler.IsRunning()
+	s.mutex.RUnlock()
+
+	if !running {
+		return nil
+	}
+	if err := s.StopPolling(); err != nil {
+		return err
+	}
+	return s.StartPolling()
+}
+
+// CalibrateByteOrder 读取电参量分组，按四种字节序解析并按可信度排序
+// 结果供用户与电表屏显比对后通过 SetByteOrder 确认
+func (s *Service) CalibrateByteOrder() ([]registers.ByteOrderCandidate, error) {
+	s.mutex.RLock()
+	p := s.poller
+	profile, ok := registers.GetProfile(s.config.profileName())
+	s.mutex.RUnlock()
+
+	if p == nil || !p.IsRunning() {
+		return nil, fmt.Errorf("设备未连接")
+	}
+	if !ok {
+		return nil, fmt.Errorf("未知的设备型号: %s", s.config.profileName())
+	}
+
+	regData, err := p.ReadGroup(registers.GroupElectrical)
+	if err != nil {
+		return nil, err
+	}
+	return registers.RankByteOrders(profile, regData), nil
+}
+
+// SetByteOrder 确认端序校准结果，空值恢复设备描述默认字节序
+// 结果写入当前配置并同步到已保存的快照（尚未保存时随下次保存一并持久化），采集进行中时立即生效
+func (s *Service) SetByteOrder(order string) error {
+	if order != "" && !registers.ValidByteOrder(order) {
+		return fmt.Errorf("不支持的字节序: %s", order)
+	}
+
+	s.mutex.Lock()
+	s.config.ByteOrder = order
+	s.mutex.Unlock()
+
+	saved, err := s.LoadSavedSerialConfig()
+	if err != nil {
+		return err
+	}
+	if saved != nil {
+		saved.ByteOrder = order
+		if err := s.SaveSavedSerialConfig(saved); err != nil {
+			return err
+		}
+	}
+	return s.restartIfRunning()
 }
 
 // GetAvailablePorts 获取可用串口列表
@@ -230,6 +635,26 @@ func (s *Service) GetAvailablePorts() ([]string, error) {
 // 保存持久化文件路径（相对于应用工作目录）
 const savedSerialConfigFile = "data/saved_serial_config.json"
 
+// savedSerialConfig 持久化快照的 JSON 结构
+type savedSerialConfig struct {
+	Port      string         `json:"port"`
+	BaudRate  int            `json:"baudRate"`
+	DataBits  int            `json:"dataBits"`
+	StopBits  int            `json:"stopBits"`
+	Parity    int            `json:"parity"`
+	SlaveID   int            `json:"slaveID"`
+	SlaveIDs  []int          `json:"slaveIDs,omitempty"`
+	Transport string         `json:"transport"`
+	Host      string         `json:"host"`
+	TCPPort   int            `json:"tcpPort"`
+	Profile   string         `json:"profile"`
+	ByteOrder string         `json:"byteOrder"`
+	Schedule  map[string]int `json:"schedule,omitempty"` // 分组 -> 轮询间隔（毫秒）
+
+	DegradedAfter int `json:"degradedAfter,omitempty"`
+	OfflineAfter  int `json:"offlineAfter,omitempty"`
+}
+
 // SaveSavedSerialConfig 将快照写入磁盘（JSON）
 func (s *Service) SaveSavedSerialConfig(cfg *SerialConfig) error {
 	// 确保目录存在
@@ -238,20 +663,28 @@ func (s *Service) SaveSavedSerialConfig(cfg *SerialConfig) error {
 	}
 
 	// 使用基础类型序列化，避免直接序列化 goserial 类型可能带来的问题
-	persist := struct {
-		Port     string `json:"port"`
-		BaudRate int    `json:"baudRate"`
-		DataBits int    `json:"dataBits"`
-		StopBits int    `json:"stopBits"`
-		Parity   int    `json:"parity"`
-		SlaveID  int    `json:"slaveID"`
-	}{
-		Port:     cfg.Port,
-		BaudRate: cfg.BaudRate,
-		DataBits: cfg.DataBits,
-		StopBits: int(cfg.StopBits),
-		Parity:   int(cfg.Parity),
-		SlaveID:  cfg.SlaveID,
+	persist := savedSerialConfig{
+		Port:      cfg.Port,
+		BaudRate:  cfg.BaudRate,
+		DataBits:  cfg.DataBits,
+		StopBits:  int(cfg.StopBits),
+		Parity:    int(cfg.Parity),
+		SlaveID:   cfg.SlaveID,
+		SlaveIDs:  cfg.SlaveIDs,
+		Transport: cfg.transportType(),
+		Host:      cfg.Host,
+		TCPPort:   cfg.TCPPort,
+		Profile:   cfg.profileName(),
+		ByteOrder: cfg.ByteOrder,
+
+		DegradedAfter: cfg.Thresholds.DegradedAfter,
+		OfflineAfter:  cfg.Thresholds.OfflineAfter,
+	}
+	if cfg.Schedule != nil {
+		persist.Schedule = make(map[string]int, len(cfg.Schedule))
+		for group, d := range cfg.Schedule {
+			persist.Schedule[group] = int(d / time.Millisecond)
+		}
 	}
 
 	data, err := json.MarshalIndent(persist, "", "  ")
@@ -275,25 +708,36 @@ func (s *Service) LoadSavedSerialConfig() (*SerialConfig, error) {
 		return nil, err
 	}
 
-	var persist struct {
-		Port     string `json:"port"`
-		BaudRate int    `json:"baudRate"`
-		DataBits int    `json:"dataBits"`
-		StopBits int    `json:"stopBits"`
-		Parity   int    `json:"parity"`
-		SlaveID  int    `json:"slaveID"`
-	}
+	var persist savedSerialConfig
 	if err := json.Unmarshal(data, &persist); err != nil {
 		return nil, err
 	}
 
 	cfg := &SerialConfig{
-		Port:     persist.Port,
-		BaudRate: persist.BaudRate,
-		DataBits: persist.DataBits,
-		StopBits: goserial.StopBits(persist.StopBits),
-		Parity:   goserial.Parity(persist.Parity),
-		SlaveID:  persist.SlaveID,
+		Port:      persist.Port,
+		BaudRate:  persist.BaudRate,
+		DataBits:  persist.DataBits,
+		StopBits:  goserial.StopBits(persist.StopBits),
+		Parity:    goserial.Parity(persist.Parity),
+		SlaveID:   persist.SlaveID,
+		SlaveIDs:  persist.SlaveIDs,
+		Transport: persist.Transport,
+		Host:      persist.Host,
+		TCPPort:   persist.TCPPort,
+		Profile:   persist.Profile,
+		ByteOrder: persist.ByteOrder,
+
+		Thresholds: StateThresholds{DegradedAfter: persist.DegradedAfter, OfflineAfter: persist.OfflineAfter},
+	}
+	if persist.Schedule != nil {
+		cfg.Schedule = make(poller.Schedule, len(persist.Schedule))
+		for group, ms := range persist.Schedule {
+			cfg.Schedule[group] = time.Duration(ms) * time.Millisecond
+		}
+	}
+	// 旧版本快照没有链路字段，按串口处理
+	if cfg.Transport == "" {
+		cfg.Transport = TransportSerial
 	}
 	return cfg, nil
 }
@@ -344,12 +788,20 @@ func (s *Service) Unsubscribe(id string) {
 	}
 }
 
-// listenData 监听数据更新
-func (s *Service) listenData() {
-	dataChan := s.poller.GetDataChannel()
-	for regData := range dataChan {
+// listenData 监听数据更新，调度器停止后退出
+func (s *Service) listenData(scheduler *poller.Scheduler, p *poller.Poller) {
+	dataChan := p.GetDataChannel()
+	for {
+		var regData *registers.ElectricalData
+		select {
+		case <-scheduler.Done():
+			return
+		case regData = <-dataChan:
+		}
+
 		// 转换数据类型
 		data := &ElectricalData{
+			SlaveID:       p.SlaveID(),
 			Voltage:       float64(regData.Voltage),
 			Current:       float64(regData.Current),
 			ActivePower:   float64(regData.ActivePower),
@@ -358,12 +810,25 @@ func (s *Service) listenData() {
 			PowerFactor:   float64(regData.PowerFactor),
 			Frequency:     float64(regData.Frequency),
 			ActiveEnergy:  float64(regData.ActiveEnergy),
-			Timestamp:     time.Now(),
+
+			ForwardActiveEnergy:   float64(regData.ForwardActiveEnergy),
+			ReverseActiveEnergy:   float64(regData.ReverseActiveEnergy),
+			ForwardReactiveEnergy: float64(regData.ForwardReactiveEnergy),
+			ReverseReactiveEnergy: float64(regData.ReverseReactiveEnergy),
+
+			Sample: newSample(regData.Acquisition),
 		}
 
 		s.mutex.Lock()
-		s.lastData = data
-		s.status.LastUpdate = time.Now()
+		if s.scheduler != scheduler {
+			// 已停止的调度器中进行中的读取结果，丢弃以免覆盖新数据
+			s.mutex.Unlock()
+			continue
+		}
+		s.lastData[data.SlaveID] = data
+		if regData.Err == nil {
+			s.recovered(p)
+		}
 		s.mutex.Unlock()
 
 		// 广播给订阅者
@@ -379,3 +844,108 @@ func (s *Service) listenData() {
 	}
 }
 
+// convertPhase 转换单相分量
+func convertPhase(p registers.PhaseData) PhaseData {
+	return PhaseData{
+		Voltage:       float64(p.Voltage),
+		Current:       float64(p.Current),
+		ActivePower:   float64(p.ActivePower),
+		ReactivePower: float64(p.ReactivePower),
+		ApparentPower: float64(p.ApparentPower),
+		PowerFactor:   float64(p.PowerFactor),
+	}
+}
+
+// listenThreePhase 监听三相数据，调度器停止后退出
+func (s *Service) listenThreePhase(scheduler *poller.Scheduler, p *poller.Poller) {
+	dataChan := p.GetThreePhaseChannel()
+	for {
+		var regData *registers.ThreePhaseData
+		select {
+		case <-scheduler.Done():
+			return
+		case regData = <-dataChan:
+		}
+
+		data := &ThreePhaseData{
+			SlaveID:            p.SlaveID(),
+			PhaseA:             convertPhase(regData.PhaseA),
+			PhaseB:             convertPhase(regData.PhaseB),
+			PhaseC:             convertPhase(regData.PhaseC),
+			VoltageAB:          float64(regData.VoltageAB),
+			VoltageBC:          float64(regData.VoltageBC),
+			VoltageCA:          float64(regData.VoltageCA),
+			TotalActivePower:   float64(regData.TotalActivePower),
+			TotalReactivePower: float64(regData.TotalReactivePower),
+			TotalApparentPower: float64(regData.TotalApparentPower),
+			TotalPowerFactor:   float64(regData.TotalPowerFactor),
+			Frequency:          float64(regData.Frequency),
+			ImportEnergy:       float64(regData.ImportEnergy),
+			ExportEnergy:       float64(regData.ExportEnergy),
+			Sample:             newSample(regData.Acquisition),
+		}
+
+		s.mutex.Lock()
+		if s.scheduler != scheduler {
+			s.mutex.Unlock()
+			continue
+		}
+		s.lastThree[data.SlaveID] = data
+		if regData.Err == nil {
+			s.recovered(p)
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// listenErrors 监听轮询错误，写入设备状态（如 Modbus 异常 "非法数据地址"），调度器停止后退出
+func (s *Service) listenErrors(scheduler *poller.Scheduler, p *poller.Poller) {
+	errChan := p.GetErrorChannel()
+	for {
+		var err error
+		select {
+		case <-scheduler.Done():
+			return
+		case err = <-errChan:
+		}
+		log.Printf("轮询错误: %v", err)
+
+		s.mutex.Lock()
+		if s.scheduler != scheduler {
+			s.mutex.Unlock()
+			continue
+		}
+		s.lastErrors[p.SlaveID()] = err.Error()
+		if s.poller == p {
+			s.status.ErrorMessage = err.Error()
+			s.broadcastStatus()
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// recovered 从站读到新数据：清除其读取错误，主设备同时更新链路状态（调用方持有锁）
+func (s *Service) recovered(p *poller.Poller) {
+	delete(s.lastErrors, p.SlaveID())
+	if s.poller != p {
+		return
+	}
+	s.status.LastUpdate = time.Now()
+	if s.status.ErrorMessage != "" {
+		// 读取恢复，清除之前的通信错误
+		s.status.ErrorMessage = ""
+		s.broadcastStatus()
+	}
+}
+
+// broadcastStatus 通知状态订阅者（调用方持有锁）
+func (s *Service) broadcastStatus() {
+	for _, ch := range s.statusSubs {
+		status := *s.status
+		select {
+		case ch <- &status:
+		default:
+			// 通道满时跳过
+		}
+	}
+}
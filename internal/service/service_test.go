@@ -1,10 +1,21 @@
 package service
 
 import (
+	"encoding/binary"
+	"io"
+	"net"
 	"os"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	goserial "go.bug.st/serial"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/poller"
+	"DDSUViewer/internal/registers"
+	"DDSUViewer/internal/simulator"
 )
 
 func TestSaveLoadClearSavedSerialConfig(t *testing.T) {
@@ -69,4 +80,777 @@ func TestSaveLoadClearSavedSerialConfig(t *testing.T) {
 
 	// 清理残留文件（容错）
 	_ = os.Remove("data/saved_serial_config.json")
-}
\ No newline at end of file
+}
+
+func TestSaveLoadSavedSerialConfig_TCPFields(t *testing.T) {
+	_ = os.Remove(savedSerialConfigFile)
+	defer os.Remove(savedSerialConfigFile)
+
+	s := NewService()
+	cfg := &SerialConfig{
+		SlaveID:   0x0C,
+		Transport: TransportModbusTCP,
+		Host:      "192.168.1.50",
+		TCPPort:   5020,
+		Profile:   "DDSU666",
+	}
+	if err := s.SaveSavedSerialConfig(cfg); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+
+	loaded, err := s.LoadSavedSerialConfig()
+	if err != nil {
+		t.Fatalf("LoadSavedSerialConfig failed: %v", err)
+	}
+	if loaded.Transport != cfg.Transport || loaded.Host != cfg.Host || loaded.TCPPort != cfg.TCPPort || loaded.Profile != cfg.Profile {
+		t.Fatalf("transport fields mismatch: got %#v want %#v", loaded, cfg)
+	}
+}
+
+func TestLoadSavedSerialConfig_LegacyDefaultsToSerial(t *testing.T) {
+	_ = os.Remove(savedSerialConfigFile)
+	defer os.Remove(savedSerialConfigFile)
+
+	// 旧版本快照不包含链路字段
+	legacy := `{"port":"COM3","baudRate":9600,"dataBits":8,"stopBits":0,"parity":0,"slaveID":12}`
+	if err := os.MkdirAll("data", 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(savedSerialConfigFile, []byte(legacy), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	s := NewService()
+	loaded, err := s.LoadSavedSerialConfig()
+	if err != nil {
+		t.Fatalf("LoadSavedSerialConfig failed: %v", err)
+	}
+	if loaded.Transport != TransportSerial {
+		t.Fatalf("expected serial transport, got %q", loaded.Transport)
+	}
+}
+
+func TestStartPolling_TCPRequiresHost(t *testing.T) {
+	s := NewService()
+	s.config = &SerialConfig{SlaveID: 1, Transport: TransportModbusTCP}
+
+	if err := s.StartPolling(); err == nil {
+		t.Fatalf("expected error when host is empty")
+	}
+	if st := s.GetDeviceStatus(); st.State != StateError || st.Connected() {
+		t.Fatalf("expected error state: %#v", st)
+	}
+}
+
+func TestStartPolling_RTUOverTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	// 网关只接受连接不应答；每个连接由单独的协程持有，测试结束时关闭全部连接并等待协程退出
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		conns []net.Conn
+	)
+	defer func() {
+		ln.Close()
+		mu.Lock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+		mu.Unlock()
+		wg.Wait()
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				io.Copy(io.Discard, conn)
+			}()
+		}
+	}()
+
+	s := NewService()
+	if err := s.UpdateSerialConfig(&SerialConfig{
+		SlaveID:   1,
+		Transport: TransportRTUOverTCP,
+		Host:      "127.0.0.1",
+		TCPPort:   ln.Addr().(*net.TCPAddr).Port,
+	}); err != nil {
+		t.Fatalf("UpdateSerialConfig failed: %v", err)
+	}
+
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	// 链路已打开但电表没有应答，不能视为已连接
+	status := s.GetDeviceStatus()
+	if status.State != StateConnecting || status.Connected() || status.Protocol != "Modbus RTU over TCP" {
+		t.Fatalf("unexpected status: %#v", status)
+	}
+
+	if err := s.StopPolling(); err != nil {
+		t.Fatalf("StopPolling failed: %v", err)
+	}
+}
+
+func TestStartPolling_Simulator(t *testing.T) {
+	s := NewService()
+	if err := s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator}); err != nil {
+		t.Fatalf("UpdateSerialConfig failed: %v", err)
+	}
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for s.GetElectricalData() == nil && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	data := s.GetElectricalData()
+	if data == nil {
+		t.Fatalf("expected simulated data")
+	}
+	if data.Voltage < 200 || data.Voltage > 240 || data.Frequency != 50 {
+		t.Fatalf("unexpected simulated data: %#v", data)
+	}
+}
+
+func TestStartPolling_SimulatorBus(t *testing.T) {
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, SlaveIDs: []int{0x0D, 0x0C}, Transport: TransportSimulator})
+	if err := s.SetBusSlaveIDs([]int{0}); err == nil {
+		t.Fatalf("expected error for invalid slave ID")
+	}
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for (s.GetDeviceElectricalData(0x0C) == nil || s.GetDeviceElectricalData(0x0D) == nil) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	for _, id := range []byte{0x0C, 0x0D} {
+		data := s.GetDeviceElectricalData(id)
+		if data == nil || data.SlaveID != id {
+			t.Fatalf("slave 0x%02X: unexpected data %#v", id, data)
+		}
+	}
+	if data := s.GetElectricalData(); data == nil || data.SlaveID != 0x0C {
+		t.Fatalf("primary data should come from slave 0x0C: %#v", data)
+	}
+
+	// 健康状态在数据送出后更新，稍候片刻
+	online := func() bool {
+		for _, d := range s.GetBusDevices() {
+			if !d.Online {
+				return false
+			}
+		}
+		return true
+	}
+	for !online() && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	devices := s.GetBusDevices()
+	if len(devices) != 2 || devices[0].SlaveID != 0x0C || !devices[0].Primary || devices[1].SlaveID != 0x0D {
+		t.Fatalf("unexpected devices: %+v", devices)
+	}
+	for _, d := range devices {
+		if !d.Online || d.LastSeen.IsZero() {
+			t.Fatalf("slave 0x%02X should be online: %+v", d.SlaveID, d)
+		}
+	}
+}
+
+func TestSetPollSchedule(t *testing.T) {
+	_ = os.Remove(savedSerialConfigFile)
+	defer os.Remove(savedSerialConfigFile)
+
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator})
+	if err := s.SetPollSchedule(poller.Schedule{registers.GroupElectrical: 10 * time.Millisecond}); err == nil {
+		t.Fatalf("expected error for interval below minimum")
+	}
+	if err := s.SaveSavedSerialConfig(s.GetSerialConfig()); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+
+	// 运行中修改，无需重启即生效并同步到快照
+	if err := s.SetPollSchedule(poller.Schedule{registers.GroupElectrical: 250 * time.Millisecond, registers.GroupEnergy: 0}); err != nil {
+		t.Fatalf("SetPollSchedule failed: %v", err)
+	}
+	if got := s.scheduler.Schedule(); got.Tick() != 250*time.Millisecond || got.Interval(registers.GroupEnergy) != 0 {
+		t.Fatalf("schedule not applied to running scheduler: %v", got)
+	}
+	if got := s.GetPollSchedule(); got[registers.GroupElectrical] != 250*time.Millisecond || got[registers.GroupEnergy] != 0 {
+		t.Fatalf("unexpected schedule: %v", got)
+	}
+
+	saved, err := s.LoadSavedSerialConfig()
+	if err != nil || saved == nil {
+		t.Fatalf("LoadSavedSerialConfig failed: %v", err)
+	}
+	if saved.Schedule[registers.GroupElectrical] != 250*time.Millisecond {
+		t.Fatalf("schedule not persisted: %v", saved.Schedule)
+	}
+	if d, ok := saved.Schedule[registers.GroupEnergy]; !ok || d != 0 {
+		t.Fatalf("on-demand group not persisted: %v", saved.Schedule)
+	}
+}
+
+func TestWriteRegisters_NotConnected(t *testing.T) {
+	s := NewService()
+	if err := s.WriteRegisters(0x0006, []uint16{1}); err == nil {
+		t.Fatalf("expected error when not connected")
+	}
+}
+
+func TestSetProfile(t *testing.T) {
+	s := NewService()
+	if err := s.SetProfile("NO-SUCH-METER"); err == nil {
+		t.Fatalf("expected error for unknown profile")
+	}
+	if err := s.SetProfile(registers.DefaultProfile); err != nil {
+		t.Fatalf("SetProfile failed: %v", err)
+	}
+	if got := s.GetSerialConfig().Profile; got != registers.DefaultProfile {
+		t.Fatalf("config profile: got %q want %q", got, registers.DefaultProfile)
+	}
+
+	// 界面字段映射与采集使用同一份配置中的型号
+	if err := s.SetProfile("SDM120"); err != nil {
+		t.Fatalf("SetProfile failed: %v", err)
+	}
+	profile, err := s.GetActiveProfile()
+	if err != nil || profile.Name != "SDM120" {
+		t.Fatalf("active profile: got %v, %v", profile, err)
+	}
+	if other := NewService(); other.GetSerialConfig().profileName() != registers.DefaultProfile {
+		t.Fatalf("profile leaked into another service: %q", other.GetSerialConfig().Profile)
+	}
+}
+
+func TestStartPolling_UnknownProfile(t *testing.T) {
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator, Profile: "NO-SUCH-METER"})
+	if err := s.StartPolling(); err == nil {
+		s.StopPolling()
+		t.Fatalf("expected error for unknown profile")
+	}
+}
+
+func TestCalibrateAndConfirmByteOrder(t *testing.T) {
+	_ = os.Remove(savedSerialConfigFile)
+	defer os.Remove(savedSerialConfigFile)
+
+	s := NewService()
+	if _, err := s.CalibrateByteOrder(); err == nil {
+		t.Fatalf("expected error when not connected")
+	}
+
+	cfg := &SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator}
+	s.UpdateSerialConfig(cfg)
+	if err := s.SaveSavedSerialConfig(cfg); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+
+	candidates, err := s.CalibrateByteOrder()
+	if err != nil {
+		t.Fatalf("CalibrateByteOrder failed: %v", err)
+	}
+	// 仿真电表使用标准大端序
+	if candidates[0].ByteOrder != registers.ByteOrderABCD {
+		t.Fatalf("best candidate: got %s want ABCD (%+v)", candidates[0].ByteOrder, candidates)
+	}
+
+	if err := s.SetByteOrder("XYZW"); err == nil {
+		t.Fatalf("expected error for unknown byte order")
+	}
+	if err := s.SetByteOrder(registers.ByteOrderCDAB); err != nil {
+		t.Fatalf("SetByteOrder failed: %v", err)
+	}
+	loaded, err := s.LoadSavedSerialConfig()
+	if err != nil || loaded.ByteOrder != registers.ByteOrderCDAB {
+		t.Fatalf("byte order not persisted: %#v, %v", loaded, err)
+	}
+	if s.GetDeviceStatus().State == StateDisconnected {
+		t.Fatalf("polling should restart after byte order change")
+	}
+}
+
+func TestMeterSettings_Simulator(t *testing.T) {
+	_ = os.Remove(savedSerialConfigFile)
+	defer os.Remove(savedSerialConfigFile)
+
+	s := NewService()
+	if _, err := s.GetMeterSettings(); err == nil {
+		t.Fatalf("expected error when not connected")
+	}
+
+	cfg := &SerialConfig{SlaveID: 0x0C, BaudRate: 9600, Transport: TransportSimulator}
+	s.UpdateSerialConfig(cfg)
+	if err := s.SaveSavedSerialConfig(cfg); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+
+	settings, err := s.GetMeterSettings()
+	if err != nil {
+		t.Fatalf("GetMeterSettings failed: %v", err)
+	}
+	if settings.Address != 0x0C || settings.BaudRate != 9600 || settings.Protocol != registers.ProtocolModbusRTU {
+		t.Fatalf("unexpected settings: %+v", settings)
+	}
+
+	if err := s.UpdateMeterSettings(0, 9600); err == nil {
+		t.Fatalf("expected error for invalid address")
+	}
+	if err := s.UpdateMeterSettings(0x20, 4800); err != nil {
+		t.Fatalf("UpdateMeterSettings failed: %v", err)
+	}
+
+	// 自动以新参数重连
+	got := s.GetSerialConfig()
+	if got.SlaveID != 0x20 || got.BaudRate != 4800 {
+		t.Fatalf("config not updated: %#v", got)
+	}
+	if s.GetDeviceStatus().State == StateDisconnected {
+		t.Fatalf("expected reconnect after settings change")
+	}
+	loaded, err := s.LoadSavedSerialConfig()
+	if err != nil || loaded.SlaveID != 0x20 || loaded.BaudRate != 4800 {
+		t.Fatalf("saved config not updated: %#v, %v", loaded, err)
+	}
+
+	if err := s.ClearEnergy(); err != nil {
+		t.Fatalf("ClearEnergy failed: %v", err)
+	}
+}
+
+func TestMeterSettings_UnsupportedProfile(t *testing.T) {
+	registers.RegisterProfile(&registers.Profile{Name: "NO-PARAMS", Registers: []registers.RegisterDef{
+		{Key: registers.KeyVoltage, Address: 0x2000, Words: 2, DataType: registers.TypeFloat32, ByteOrder: registers.ByteOrderABCD, Scale: 1, Group: registers.GroupElectrical},
+	}})
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator, Profile: "NO-PARAMS"})
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+
+	if _, err := s.GetMeterSettings(); err == nil {
+		t.Fatalf("expected error for profile without parameter registers")
+	}
+}
+
+func TestReadRawRegisters_Simulator(t *testing.T) {
+	s := NewService()
+	if _, err := s.ReadRawRegisters(modbus.FunctionReadHoldingRegisters, registers.RegVoltage, 2); err == nil {
+		t.Fatalf("expected error when not connected")
+	}
+
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator})
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+
+	if _, err := s.ReadRawRegisters(0x01, 0, 1); err == nil {
+		t.Fatalf("expected error for unsupported function code")
+	}
+	if _, err := s.ReadRawRegisters(modbus.FunctionReadHoldingRegisters, 0, 126); err == nil {
+		t.Fatalf("expected error for oversized quantity")
+	}
+
+	reading, err := s.ReadRawRegisters(modbus.FunctionReadHoldingRegisters, registers.RegVoltage, 4)
+	if err != nil {
+		t.Fatalf("ReadRawRegisters failed: %v", err)
+	}
+	if len(reading.Words) != 4 || reading.Registers[1].Address != registers.RegVoltage+1 {
+		t.Fatalf("unexpected reading: %+v", reading)
+	}
+	if v := reading.Registers[0].Float32[registers.ByteOrderABCD]; v < 200 || v > 240 {
+		t.Fatalf("voltage as float32 ABCD: %v", v)
+	}
+
+	// 仿真器不支持 0x04，异常应答应作为错误返回
+	if _, err := s.ReadRawRegisters(modbus.FunctionReadInputRegisters, registers.RegVoltage, 2); err == nil {
+		t.Fatalf("expected exception for input registers")
+	}
+}
+
+func TestSendRawFrame_Simulator(t *testing.T) {
+	s := NewService()
+	if _, err := s.SendRawFrame("0C 03 20 00 00 02", true); err == nil {
+		t.Fatalf("expected error when not connected")
+	}
+
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator})
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+
+	if _, err := s.SendRawFrame("0C 0G", true); err == nil {
+		t.Fatalf("expected error for invalid hex")
+	}
+
+	tx, err := s.SendRawFrame("0C 03 20 00 00 02", true)
+	if err != nil {
+		t.Fatalf("SendRawFrame failed: %v", err)
+	}
+	if tx.Error != "" || tx.CRC != CRCOK || len(tx.Response) != 9 || tx.Response[1] != modbus.FunctionReadHoldingRegisters {
+		t.Fatalf("unexpected transaction: %+v", tx)
+	}
+	if len(tx.Request) != 8 || tx.Duration <= 0 || len(tx.ResponseFields) == 0 {
+		t.Fatalf("unexpected transaction: %+v", tx)
+	}
+
+	// 未知功能码：仿真器以异常应答
+	tx, err = s.SendRawFrame("0C 2A 00 00", true)
+	if err != nil {
+		t.Fatalf("SendRawFrame failed: %v", err)
+	}
+	if tx.CRC != CRCOK || tx.Response[1] != 0x2A|0x80 {
+		t.Fatalf("expected exception response: %+v", tx)
+	}
+
+	if history := s.GetConsoleHistory(); len(history) != 2 || history[0].Request[1] != 0x03 {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+	s.ClearConsoleHistory()
+	if len(s.GetConsoleHistory()) != 0 {
+		t.Fatalf("history should be empty")
+	}
+}
+
+func TestNewService_RestoresSavedByteOrder(t *testing.T) {
+	_ = os.Remove(savedSerialConfigFile)
+	defer os.Remove(savedSerialConfigFile)
+
+	cfg := &SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator, ByteOrder: registers.ByteOrderCDAB}
+	if err := NewService().SaveSavedSerialConfig(cfg); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+
+	// 重启后端序校准结果应载入当前配置并由轮询器使用
+	s := NewService()
+	if got := s.GetSerialConfig().ByteOrder; got != registers.ByteOrderCDAB {
+		t.Fatalf("byte order not restored: got %q", got)
+	}
+	profile, err := s.activeProfile()
+	if err != nil {
+		t.Fatalf("activeProfile failed: %v", err)
+	}
+	for _, d := range profile.Registers {
+		if d.ByteOrder != registers.ByteOrderCDAB {
+			t.Fatalf("%s byte order: got %q want CDAB", d.Key, d.ByteOrder)
+		}
+	}
+
+	// 按前端流程修改并保存其他配置项，端序不得被覆盖
+	next := *s.GetSerialConfig()
+	next.BaudRate = 4800
+	if err := s.UpdateSerialConfig(&next); err != nil {
+		t.Fatalf("UpdateSerialConfig failed: %v", err)
+	}
+	if err := s.SaveSavedSerialConfig(s.GetSerialConfig()); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+	loaded, err := s.LoadSavedSerialConfig()
+	if err != nil || loaded.ByteOrder != registers.ByteOrderCDAB || loaded.BaudRate != 4800 {
+		t.Fatalf("byte order lost after save: %#v, %v", loaded, err)
+	}
+}
+
+func TestNewService_RestoresSavedSchedule(t *testing.T) {
+	_ = os.Remove(savedSerialConfigFile)
+	defer os.Remove(savedSerialConfigFile)
+
+	cfg := &SerialConfig{
+		SlaveID:   0x0C,
+		Transport: TransportSimulator,
+		Schedule:  poller.Schedule{registers.GroupElectrical: 250 * time.Millisecond, registers.GroupEnergy: 0},
+	}
+	if err := NewService().SaveSavedSerialConfig(cfg); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+
+	s := NewService()
+	if got := s.GetPollSchedule(); got[registers.GroupElectrical] != 250*time.Millisecond || got[registers.GroupEnergy] != 0 {
+		t.Fatalf("schedule not restored: %v", got)
+	}
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+	if got := s.scheduler.Schedule(); got.Tick() != 250*time.Millisecond || got.Interval(registers.GroupEnergy) != 0 {
+		t.Fatalf("restored schedule not applied: %v", got)
+	}
+
+	// 保存其他配置项时轮询间隔保持不变
+	if err := s.SaveSavedSerialConfig(s.GetSerialConfig()); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+	loaded, err := s.LoadSavedSerialConfig()
+	if err != nil || loaded.Schedule[registers.GroupElectrical] != 250*time.Millisecond {
+		t.Fatalf("schedule lost after save: %#v, %v", loaded, err)
+	}
+}
+
+func TestNewService_RestoresSavedSlaveIDs(t *testing.T) {
+	_ = os.Remove(savedSerialConfigFile)
+	defer os.Remove(savedSerialConfigFile)
+
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator})
+	if err := s.SaveSavedSerialConfig(s.GetSerialConfig()); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+	if err := s.SetBusSlaveIDs([]int{0x0D, 0x0E}); err != nil {
+		t.Fatalf("SetBusSlaveIDs failed: %v", err)
+	}
+
+	// 重启后无需重新输入总线从站，保存其他配置项时也不会被清空
+	restarted := NewService()
+	if err := restarted.SaveSavedSerialConfig(restarted.GetSerialConfig()); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+	devices := NewService().GetBusDevices()
+	if len(devices) != 3 || devices[0].SlaveID != 0x0C || devices[1].SlaveID != 0x0D || devices[2].SlaveID != 0x0E {
+		t.Fatalf("bus slave IDs not restored: %+v", devices)
+	}
+}
+
+// settledGoroutines 等待已停止的协程退出后返回当前协程数（不超过 limit 时立即返回）
+func settledGoroutines(limit int) int {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		n := runtime.NumGoroutine()
+		if n <= limit || time.Now().After(deadline) {
+			return n
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestRestartPolling_NoGoroutineLeak(t *testing.T) {
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, SlaveIDs: []int{0x0D}, Transport: TransportSimulator})
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+	baseline := runtime.NumGoroutine()
+
+	// 每次重启都会新建调度器与监听协程，旧的须随调度停止退出
+	for i := 0; i < 5; i++ {
+		if err := s.restartIfRunning(); err != nil {
+			t.Fatalf("restart %d failed: %v", i, err)
+		}
+	}
+	if n := settledGoroutines(baseline); n > baseline {
+		t.Fatalf("goroutines grew across restarts: %d -> %d", baseline, n)
+	}
+}
+
+// startModbusTCPGateway 启动挂有仿真电表的本地 Modbus TCP 网关，返回端口
+// 每个连接应答 perConn 次请求后由网关主动断开，模拟网关重启或空闲断链
+func startModbusTCPGateway(t *testing.T, meter *simulator.Meter, perConn int) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	var wg sync.WaitGroup
+	t.Cleanup(func() {
+		ln.Close()
+		wg.Wait()
+	})
+
+	serve := func(conn net.Conn) {
+		defer wg.Done()
+		defer conn.Close()
+		for i := 0; i < perConn; i++ {
+			header := make([]byte, modbus.MBAPHeaderLength)
+			if _, err := io.ReadFull(conn, header); err != nil {
+				return
+			}
+			pdu := make([]byte, int(binary.BigEndian.Uint16(header[4:6]))-1)
+			if _, err := io.ReadFull(conn, pdu); err != nil {
+				return
+			}
+			response := meter.Handle(modbus.AppendCRC(append([]byte{header[6]}, pdu...)))
+			if response == nil {
+				continue
+			}
+			reply, _ := modbus.BuildTCPFrame(binary.BigEndian.Uint16(header[0:2]), response)
+			conn.Write(reply)
+		}
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			wg.Add(1)
+			go serve(conn)
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestStartPolling_ModbusTCPRecoversAfterGatewayClose(t *testing.T) {
+	meter := simulator.NewMeter(simulator.Config{SlaveID: 0x0C, Waveform: simulator.DefaultWaveform()})
+	port := startModbusTCPGateway(t, meter, 3)
+
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{
+		SlaveID:   0x0C,
+		Transport: TransportModbusTCP,
+		Host:      "127.0.0.1",
+		TCPPort:   port,
+		Schedule:  poller.Schedule{registers.GroupElectrical: 250 * time.Millisecond},
+	})
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+
+	// 网关每应答 3 次即断开，轮询须自动重连并持续读到新数据
+	var last time.Time
+	fresh := 0
+	deadline := time.Now().Add(5 * time.Second)
+	for fresh < 5 && time.Now().Before(deadline) {
+		if data := s.GetElectricalData(); data != nil && data.Error == "" && data.Timestamp.After(last) {
+			last = data.Timestamp
+			fresh++
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if fresh < 5 {
+		t.Fatalf("polling did not recover after gateway closed the connection: %d fresh samples", fresh)
+	}
+}
+
+func TestNewService_RestoresSavedProfile(t *testing.T) {
+	_ = os.Remove(savedSerialConfigFile)
+	defer os.Remove(savedSerialConfigFile)
+
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator, ByteOrder: registers.ByteOrderCDAB})
+	if err := s.SaveSavedSerialConfig(s.GetSerialConfig()); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+	if err := s.SetProfile("SDM120"); err != nil {
+		t.Fatalf("SetProfile failed: %v", err)
+	}
+
+	// 重启后恢复新型号，原型号的端序校准结果随之清除
+	restarted := NewService()
+	profile, err := restarted.GetActiveProfile()
+	if err != nil || profile.Name != "SDM120" {
+		t.Fatalf("profile not restored: %v, %v", profile, err)
+	}
+	if got := restarted.GetSerialConfig().ByteOrder; got != "" {
+		t.Fatalf("byte order of the previous profile restored: %q", got)
+	}
+}
+
+func TestSendRawFrame_ModbusTCP(t *testing.T) {
+	meter := simulator.NewMeter(simulator.Config{SlaveID: 0x0C, Waveform: simulator.DefaultWaveform()})
+	port := startModbusTCPGateway(t, meter, 1000)
+
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportModbusTCP, Host: "127.0.0.1", TCPPort: port})
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+
+	// 链路会去掉帧末两个字节作为 CRC，缺少 CRC 的帧不得发送
+	if _, err := s.SendRawFrame("0C 03 20 00 00 02", false); err == nil {
+		t.Fatalf("expected error for frame without CRC on Modbus TCP")
+	}
+	if len(s.GetConsoleHistory()) != 0 {
+		t.Fatalf("rejected frame should not be recorded")
+	}
+
+	tx, err := s.SendRawFrame("0C 03 20 00 00 02", true)
+	if err != nil {
+		t.Fatalf("SendRawFrame failed: %v", err)
+	}
+	if tx.Error != "" || len(tx.Response) != 9 || tx.Response[1] != modbus.FunctionReadHoldingRegisters || tx.Response[2] != 4 {
+		t.Fatalf("unexpected transaction: %+v", tx)
+	}
+	// 应答 CRC 由链路补齐，不能报告为校验通过
+	if tx.CRC != CRCNotApplicable {
+		t.Fatalf("CRC verdict on Modbus TCP: got %q want n/a", tx.CRC)
+	}
+}
+
+func TestGetSerialConfig_ReturnsCopy(t *testing.T) {
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{
+		SlaveID:   0x0C,
+		SlaveIDs:  []int{0x0D},
+		Transport: TransportSimulator,
+		Schedule:  poller.Schedule{registers.GroupElectrical: 250 * time.Millisecond},
+	})
+
+	cfg := s.GetSerialConfig()
+	cfg.SlaveIDs[0] = 0x20
+	cfg.Schedule[registers.GroupElectrical] = time.Second
+	cfg.Profile = "SDM120"
+	if got := s.GetSerialConfig(); got.SlaveIDs[0] != 0x0D || got.Schedule[registers.GroupElectrical] != 250*time.Millisecond || got.Profile != "" {
+		t.Fatalf("service config modified through returned copy: %+v", got)
+	}
+	if s.GetSerialConfig().Schedule == nil || NewService().GetSerialConfig().Schedule != nil {
+		t.Fatalf("copy should keep nil schedule as nil")
+	}
+
+	// 与各项设置并发读取（配合 -race 检查）
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			s.SetBusSlaveIDs([]int{0x0D, 0x0E})
+			s.SetPollSchedule(poller.Schedule{registers.GroupEnergy: time.Minute})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		cfg := s.GetSerialConfig()
+		_ = len(cfg.SlaveIDs) + len(cfg.Schedule) + len(cfg.Profile)
+	}
+	wg.Wait()
+}
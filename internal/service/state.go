@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"DDSUViewer/internal/poller"
+	"DDSUViewer/internal/transport"
+)
+
+// ConnectionState 设备连接状态
+type ConnectionState string
+
+const (
+	StateDisconnected ConnectionState = "disconnected" // 未在采集
+	StateConnecting   ConnectionState = "connecting"   // 链路已打开，等待电表首次应答
+	StateOnline       ConnectionState = "online"       // 电表应答正常
+	StateDegraded     ConnectionState = "degraded"     // 出现连续失败，尚未达到离线阈值
+	StateOffline      ConnectionState = "offline"      // 连续失败达到阈值，通道标红
+	StateError        ConnectionState = "error"        // 配置错误或链路无法打开
+	StateReconnecting ConnectionState = "reconnecting" // 链路意外断开（如拔出 USB 适配器），正在按退避间隔重新打开
+)
+
+// 默认状态切换阈值
+const (
+	DefaultDegradedAfter = 1                          // 连续失败 1 次即提示通信不稳定
+	DefaultOfflineAfter  = poller.DefaultOfflineAfter // 连续失败 3 次视为离线，总线调度器同时据此对离线从站退避
+)
+
+// StateThresholds 状态切换阈值，零值使用默认值
+type StateThresholds struct {
+	DegradedAfter int // 连续失败达到该次数进入 Degraded
+	OfflineAfter  int // 连续失败达到该次数进入 Offline（电表从未应答时由 Connecting 直接进入）
+}
+
+// normalized 返回补齐默认值的阈值
+func (t StateThresholds) normalized() StateThresholds {
+	if t.DegradedAfter <= 0 {
+		t.DegradedAfter = DefaultDegradedAfter
+	}
+	if t.OfflineAfter <= 0 {
+		t.OfflineAfter = DefaultOfflineAfter
+	}
+	return t
+}
+
+// Validate 检查阈值：离线阈值不得小于降级阈值
+func (t StateThresholds) Validate() error {
+	if t.DegradedAfter < 0 || t.OfflineAfter < 0 {
+		return fmt.Errorf("失败阈值不能为负数: %d/%d", t.DegradedAfter, t.OfflineAfter)
+	}
+	n := t.normalized()
+	if n.OfflineAfter < n.DegradedAfter {
+		return fmt.Errorf("离线阈值 (%d) 不能小于降级阈值 (%d)", n.OfflineAfter, n.DegradedAfter)
+	}
+	return nil
+}
+
+// nextState 根据主设备最近的连续成功/失败次数计算下一个状态
+// 任一次成功即恢复 Online；失败按阈值进入 Degraded 或 Offline，电表从未应答时保持 Connecting 直到离线
+func nextState(current ConnectionState, h poller.DeviceHealth, t StateThresholds) ConnectionState {
+	t = t.normalized()
+	switch current {
+	case StateDisconnected, StateError, StateReconnecting:
+		return current // 未在采集，轮询结果不再改变状态
+	}
+	if h.Successes > 0 {
+		return StateOnline
+	}
+	switch {
+	case h.Failures >= t.OfflineAfter:
+		return StateOffline
+	case current == StateConnecting:
+		return StateConnecting
+	case current == StateOffline:
+		return StateOffline // 离线后须读到有效数据才恢复
+	case h.Failures >= t.DegradedAfter:
+		return StateDegraded
+	default:
+		return current
+	}
+}
+
+// setState 切换状态并通知订阅者，状态未变化时不通知（调用方持有锁）
+func (s *Service) setState(state ConnectionState) {
+	if s.status.State == state {
+		return
+	}
+	log.Printf("设备状态: %s -> %s", s.status.State, state)
+	s.status.State = state
+	s.status.Since = time.Now()
+	s.broadcastStatus()
+}
+
+// failStart 启动采集失败：进入 Error 状态并记录原因（调用方持有锁）
+func (s *Service) failStart(err error) error {
+	s.status.ErrorMessage = err.Error()
+	s.setState(StateError)
+	return err
+}
+
+// listenHealth 按主设备的轮询结果驱动状态机；任一从站读取失败且链路已断开时转入自动重连
+// 调度器停止后退出
+func (s *Service) listenHealth(scheduler *poller.Scheduler) {
+	healthChan := scheduler.GetHealthChannel()
+	for {
+		var h poller.DeviceHealth
+		select {
+		case <-scheduler.Done():
+			return
+		case h = <-healthChan:
+		}
+
+		s.mutex.Lock()
+		if s.scheduler == scheduler {
+			if h.Failures > 0 && transport.Lost(s.conn) {
+				s.beginReconnect()
+			} else if h.SlaveID == byte(s.config.SlaveID) {
+				s.status.Failures = h.Failures
+				s.setState(nextState(s.status.State, h, s.config.Thresholds))
+			}
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// GetStateThresholds 返回当前状态切换阈值（已补齐默认值）
+func (s *Service) GetStateThresholds() StateThresholds {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.config.Thresholds.normalized()
+}
+
+// SetStateThresholds 设置状态切换阈值，立即生效并同步到已保存的快照
+func (s *Service) SetStateThresholds(t StateThresholds) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.config.Thresholds = t
+	if s.scheduler != nil {
+		s.scheduler.SetOfflineAfter(t.normalized().OfflineAfter)
+	}
+	s.mutex.Unlock()
+
+	saved, err := s.LoadSavedSerialConfig()
+	if err != nil {
+		return err
+	}
+	if saved != nil {
+		saved.Thresholds = t
+		return s.SaveSavedSerialConfig(saved)
+	}
+	return nil
+}
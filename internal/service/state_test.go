@@ -0,0 +1,126 @@
+package service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"DDSUViewer/internal/poller"
+)
+
+func TestNextState(t *testing.T) {
+	th := StateThresholds{DegradedAfter: 1, OfflineAfter: 3}
+	fail := func(n int) poller.DeviceHealth { return poller.DeviceHealth{Failures: n} }
+	ok := poller.DeviceHealth{Successes: 1}
+
+	cases := []struct {
+		name    string
+		current ConnectionState
+		health  poller.DeviceHealth
+		want    ConnectionState
+	}{
+		{"首次应答", StateConnecting, ok, StateOnline},
+		{"从未应答但未达阈值", StateConnecting, fail(2), StateConnecting},
+		{"从未应答达到阈值", StateConnecting, fail(3), StateOffline},
+		{"偶发失败", StateOnline, fail(1), StateDegraded},
+		{"连续失败达到阈值", StateDegraded, fail(3), StateOffline},
+		{"离线后恢复", StateOffline, ok, StateOnline},
+		{"降级后恢复", StateDegraded, ok, StateOnline},
+		{"已停止", StateDisconnected, ok, StateDisconnected},
+		{"启动失败", StateError, fail(5), StateError},
+		{"重连中", StateReconnecting, fail(5), StateReconnecting},
+	}
+	for _, c := range cases {
+		if got := nextState(c.current, c.health, th); got != c.want {
+			t.Errorf("%s: %s -> %s, want %s", c.name, c.current, got, c.want)
+		}
+	}
+
+	// 零值使用默认阈值
+	if got := nextState(StateDegraded, fail(DefaultOfflineAfter), StateThresholds{}); got != StateOffline {
+		t.Errorf("default threshold: got %s", got)
+	}
+	if err := (StateThresholds{DegradedAfter: 3, OfflineAfter: 2}).Validate(); err == nil {
+		t.Errorf("expected error when offline threshold is below degraded threshold")
+	}
+}
+
+func TestStateTransitions_Simulator(t *testing.T) {
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator})
+	statusCh := s.SubscribeStatus("test")
+	defer s.Unsubscribe("test")
+
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+
+	var seen []ConnectionState
+	timeout := time.After(3 * time.Second)
+	for len(seen) == 0 || seen[len(seen)-1] != StateOnline {
+		select {
+		case st := <-statusCh:
+			seen = append(seen, st.State)
+		case <-timeout:
+			t.Fatalf("expected online state, saw %v", seen)
+		}
+	}
+	if seen[0] != StateConnecting {
+		t.Fatalf("expected connecting before online, saw %v", seen)
+	}
+	if !s.GetDeviceStatus().Connected() {
+		t.Fatalf("online device should report connected")
+	}
+
+	s.StopPolling()
+	if st := <-statusCh; st.State != StateDisconnected {
+		t.Fatalf("expected disconnected after stop, got %s", st.State)
+	}
+}
+
+func TestNewService_RestoresSavedThresholds(t *testing.T) {
+	_ = os.Remove(savedSerialConfigFile)
+	defer os.Remove(savedSerialConfigFile)
+
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator})
+	if err := s.SaveSavedSerialConfig(s.GetSerialConfig()); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+	want := StateThresholds{DegradedAfter: 2, OfflineAfter: 5}
+	if err := s.SetStateThresholds(want); err != nil {
+		t.Fatalf("SetStateThresholds failed: %v", err)
+	}
+
+	// 重启后阈值应恢复，保存其他配置项时也不会被默认值覆盖
+	restarted := NewService()
+	if got := restarted.GetStateThresholds(); got != want {
+		t.Fatalf("thresholds not restored: got %+v want %+v", got, want)
+	}
+	if err := restarted.SaveSavedSerialConfig(restarted.GetSerialConfig()); err != nil {
+		t.Fatalf("SaveSavedSerialConfig failed: %v", err)
+	}
+	if got := NewService().GetStateThresholds(); got != want {
+		t.Fatalf("thresholds lost after save: got %+v want %+v", got, want)
+	}
+}
+
+func TestStateThresholds_DriveSchedulerBackoff(t *testing.T) {
+	s := NewService()
+	s.UpdateSerialConfig(&SerialConfig{SlaveID: 0x0C, Transport: TransportSimulator, Thresholds: StateThresholds{OfflineAfter: 5}})
+	if err := s.StartPolling(); err != nil {
+		t.Fatalf("StartPolling failed: %v", err)
+	}
+	defer s.StopPolling()
+
+	// 调度器的离线退避与状态机使用同一离线阈值
+	if got := s.scheduler.OfflineAfter(); got != 5 {
+		t.Fatalf("scheduler offline threshold: got %d want 5", got)
+	}
+	if err := s.SetStateThresholds(StateThresholds{DegradedAfter: 2, OfflineAfter: 4}); err != nil {
+		t.Fatalf("SetStateThresholds failed: %v", err)
+	}
+	if got := s.scheduler.OfflineAfter(); got != 4 {
+		t.Fatalf("scheduler offline threshold after update: got %d want 4", got)
+	}
+}
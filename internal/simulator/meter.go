@@ -0,0 +1,373 @@
+package simulator
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/registers"
+)
+
+// 仿真电表支持的寄存器区间（与 DDSU666 一致）
+const (
+	paramStart      = 0x0000 // 参数寄存器 0x0000-0x0010，可读写
+	paramWords      = 17
+	electricalStart = registers.RegVoltage // 0x2000
+	electricalWords = 16                   // 0x2000-0x200F
+	energyStart     = registers.RegActiveEnergy
+	energyWords     = 2 // 0x4000-0x4001
+)
+
+// Fault 故障类型
+type Fault int
+
+const (
+	FaultNone         Fault = iota
+	FaultTimeout            // 不应答
+	FaultCRC                // 应答 CRC 错误
+	FaultPartialFrame       // 只应答半帧
+)
+
+// FaultRates 随机故障概率（0-1）
+type FaultRates struct {
+	Timeout      float64
+	CRC          float64
+	PartialFrame float64
+}
+
+// LoadStep 负载阶跃：自仿真开始 At 之后负载电流切换为 Current
+type LoadStep struct {
+	At      time.Duration
+	Current float64
+}
+
+// Waveform 电参量波形
+type Waveform struct {
+	Voltage       float64       // 额定电压 V
+	VoltageDrift  float64       // 电压正弦漂移幅度 V
+	DriftPeriod   time.Duration // 电压漂移周期
+	Frequency     float64       // 频率 Hz
+	PowerFactor   float64       // 功率因数
+	Current       float64       // 初始负载电流 A
+	LoadSteps     []LoadStep    // 负载阶跃，按 At 升序
+	InitialEnergy float64       // 初始有功总电能 kWh
+}
+
+// DefaultWaveform 默认波形：220V 附近缓慢漂移，1A 阻感负载
+func DefaultWaveform() Waveform {
+	return Waveform{
+		Voltage:       220,
+		VoltageDrift:  2,
+		DriftPeriod:   time.Minute,
+		Frequency:     50,
+		PowerFactor:   0.95,
+		Current:       1,
+		InitialEnergy: 100,
+	}
+}
+
+// Config 仿真电表配置
+type Config struct {
+	SlaveID  byte
+	Waveform Waveform
+	Faults   FaultRates
+	Seed     int64            // 随机故障种子，固定种子可复现
+	Clock    func() time.Time // 时钟，测试中可替换；nil 时使用 time.Now
+}
+
+// Meter 仿真 DDSU666 从站
+type Meter struct {
+	config  Config
+	mutex   sync.Mutex
+	rng     *rand.Rand
+	start   time.Time
+	last    time.Time
+	energy  float64 // kWh
+	queued  []Fault // 一次性故障，优先于随机故障
+	current registers.ElectricalData
+	params  [paramWords]uint16
+}
+
+// NewMeter 创建仿真电表
+func NewMeter(config Config) *Meter {
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+	now := config.Clock()
+	m := &Meter{
+		config: config,
+		rng:    rand.New(rand.NewSource(config.Seed)),
+		start:  now,
+		last:   now,
+		energy: config.Waveform.InitialEnergy,
+	}
+	m.params[registers.RegRevision] = 0x0100 // REV. 版本号
+	m.params[registers.RegProtocol] = registers.ProtocolModbusRTU
+	m.params[registers.RegAddress] = uint16(config.SlaveID) // 通讯地址
+	m.params[registers.RegBaudRate] = 3                     // 波特率：9600bps
+	m.sample(now)
+	return m
+}
+
+// Param 返回参数寄存器的当前值
+func (m *Meter) Param(addr uint16) uint16 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if addr >= paramWords {
+		return 0
+	}
+	return m.params[addr]
+}
+
+// SlaveID 返回从站地址，写入通讯地址参数后随之改变
+func (m *Meter) SlaveID() byte {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return byte(m.params[registers.RegAddress])
+}
+
+// InjectFault 排队 count 次一次性故障，依次作用于后续请求
+func (m *Meter) InjectFault(fault Fault, count int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for i := 0; i < count; i++ {
+		m.queued = append(m.queued, fault)
+	}
+}
+
+// Snapshot 返回当前时刻的真实电参量
+func (m *Meter) Snapshot() registers.ElectricalData {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sample(m.config.Clock())
+	return m.current
+}
+
+// Handle 处理一帧 RTU 请求，返回应答帧；返回 nil 表示不应答
+func (m *Meter) Handle(request []byte) []byte {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// 长度不足、CRC 错误或非本机地址的请求一律静默丢弃（与真实从站一致）
+	if len(request) < 4 || request[0] != byte(m.params[registers.RegAddress]) {
+		return nil
+	}
+	crc := binary.LittleEndian.Uint16(request[len(request)-2:])
+	if crc != modbus.CalculateCRC16(request[:len(request)-2]) {
+		return nil
+	}
+
+	fault := m.nextFault()
+	if fault == FaultTimeout {
+		return nil
+	}
+
+	m.sample(m.config.Clock())
+	response := m.respond(request)
+
+	switch fault {
+	case FaultCRC:
+		response[len(response)-1] ^= 0xFF
+	case FaultPartialFrame:
+		response = response[:len(response)/2]
+	}
+	return response
+}
+
+// nextFault 取出本次请求的故障（调用方持有锁）
+func (m *Meter) nextFault() Fault {
+	if len(m.queued) > 0 {
+		fault := m.queued[0]
+		m.queued = m.queued[1:]
+		return fault
+	}
+
+	rates := m.config.Faults
+	r := m.rng.Float64()
+	switch {
+	case r < rates.Timeout:
+		return FaultTimeout
+	case r < rates.Timeout+rates.CRC:
+		return FaultCRC
+	case r < rates.Timeout+rates.CRC+rates.PartialFrame:
+		return FaultPartialFrame
+	}
+	return FaultNone
+}
+
+// respond 构造正常或异常应答（调用方持有锁）
+func (m *Meter) respond(request []byte) []byte {
+	function := request[1]
+	switch function {
+	case modbus.FunctionReadHoldingRegisters:
+	case modbus.FunctionWriteSingleRegister:
+		return m.writeSingle(request)
+	case modbus.FunctionWriteMultipleRegisters:
+		return m.writeMultiple(request)
+	default:
+		return exceptionFrame(request[0], function, modbus.ExceptionIllegalFunction)
+	}
+	if len(request) != 8 {
+		return exceptionFrame(request[0], function, modbus.ExceptionIllegalDataValue)
+	}
+
+	startAddr := binary.BigEndian.Uint16(request[2:4])
+	quantity := binary.BigEndian.Uint16(request[4:6])
+	if quantity == 0 || quantity > modbus.MaxReadRegisters {
+		return exceptionFrame(request[0], function, modbus.ExceptionIllegalDataValue)
+	}
+
+	data, ok := m.readWords(startAddr, quantity)
+	if !ok {
+		return exceptionFrame(request[0], function, modbus.ExceptionIllegalDataAddr)
+	}
+
+	frame := []byte{request[0], function, byte(len(data))}
+	frame = append(frame, data...)
+	return binary.LittleEndian.AppendUint16(frame, modbus.CalculateCRC16(frame))
+}
+
+// readWords 读取寄存器区间，区间必须完整落在同一个寄存器块内
+func (m *Meter) readWords(startAddr uint16, quantity uint16) ([]byte, bool) {
+	end := uint32(startAddr) + uint32(quantity)
+
+	var block []byte
+	var base uint16
+	switch {
+	case end <= paramStart+paramWords:
+		block, base = m.paramBlock(), paramStart
+	case startAddr >= electricalStart && end <= electricalStart+electricalWords:
+		block, base = m.electricalBlock(), electricalStart
+	case startAddr >= energyStart && end <= energyStart+energyWords:
+		block, base = putFloat32(nil, float32(m.energy)), energyStart
+	default:
+		return nil, false
+	}
+
+	offset := int(startAddr-base) * 2
+	return block[offset : offset+int(quantity)*2], true
+}
+
+// paramBlock 生成参数寄存器内容
+func (m *Meter) paramBlock() []byte {
+	block := make([]byte, 0, paramWords*2)
+	for _, v := range m.params {
+		block = binary.BigEndian.AppendUint16(block, v)
+	}
+	return block
+}
+
+// writeSingle 处理 0x06 写单个寄存器，正常应答为请求回显
+func (m *Meter) writeSingle(request []byte) []byte {
+	if len(request) != 8 {
+		return exceptionFrame(request[0], request[1], modbus.ExceptionIllegalDataValue)
+	}
+	addr := binary.BigEndian.Uint16(request[2:4])
+	if addr >= paramStart+paramWords {
+		return exceptionFrame(request[0], request[1], modbus.ExceptionIllegalDataAddr)
+	}
+	m.setParam(addr, binary.BigEndian.Uint16(request[4:6]))
+	return append([]byte(nil), request...)
+}
+
+// writeMultiple 处理 0x10 写多个寄存器，正常应答回显起始地址与数量
+func (m *Meter) writeMultiple(request []byte) []byte {
+	if len(request) < 9 {
+		return exceptionFrame(request[0], request[1], modbus.ExceptionIllegalDataValue)
+	}
+	startAddr := binary.BigEndian.Uint16(request[2:4])
+	quantity := binary.BigEndian.Uint16(request[4:6])
+	byteCount := int(request[6])
+	if quantity == 0 || quantity > modbus.MaxWriteRegisters || byteCount != int(quantity)*2 || len(request) != 9+byteCount {
+		return exceptionFrame(request[0], request[1], modbus.ExceptionIllegalDataValue)
+	}
+	if uint32(startAddr)+uint32(quantity) > paramStart+paramWords {
+		return exceptionFrame(request[0], request[1], modbus.ExceptionIllegalDataAddr)
+	}
+	for i := 0; i < int(quantity); i++ {
+		m.setParam(startAddr+uint16(i), binary.BigEndian.Uint16(request[7+i*2:]))
+	}
+
+	frame := append([]byte(nil), request[:6]...)
+	return binary.LittleEndian.AppendUint16(frame, modbus.CalculateCRC16(frame))
+}
+
+// setParam 写入参数寄存器（调用方持有锁）
+// 写清电量命令时电能清零并且该寄存器不保留写入值；通讯地址在应答发出后生效
+func (m *Meter) setParam(addr uint16, value uint16) {
+	if addr == registers.RegClearEnergy {
+		if value == registers.ClearEnergyCommand {
+			m.energy = 0
+		}
+		return
+	}
+	m.params[addr] = value
+}
+
+// electricalBlock 生成 0x2000-0x200F 的寄存器内容
+func (m *Meter) electricalBlock() []byte {
+	block := make([]byte, 0, electricalWords*2)
+	block = putFloat32(block, m.current.Voltage)
+	block = putFloat32(block, m.current.Current)
+	block = putFloat32(block, m.current.ActivePower)
+	block = putFloat32(block, m.current.ReactivePower)
+	block = putFloat32(block, m.current.ApparentPower)
+	block = putFloat32(block, m.current.PowerFactor)
+	block = putFloat32(block, 0) // 0x200C 保留
+	block = putFloat32(block, m.current.Frequency)
+	return block
+}
+
+// sample 按波形计算 now 时刻的电参量并累计电能（调用方持有锁）
+func (m *Meter) sample(now time.Time) {
+	w := m.config.Waveform
+	elapsed := now.Sub(m.start)
+
+	voltage := w.Voltage
+	if w.DriftPeriod > 0 {
+		phase := 2 * math.Pi * float64(elapsed) / float64(w.DriftPeriod)
+		voltage += w.VoltageDrift * math.Sin(phase)
+	}
+
+	current := w.Current
+	for _, step := range w.LoadSteps {
+		if elapsed >= step.At {
+			current = step.Current
+		}
+	}
+
+	apparent := voltage * current
+	active := apparent * w.PowerFactor
+	reactive := math.Sqrt(math.Max(apparent*apparent-active*active, 0))
+
+	// 按上次采样时的有功功率累计电能
+	if dt := now.Sub(m.last); dt > 0 {
+		m.energy += float64(m.current.ActivePower) * dt.Hours() / 1000
+		m.last = now
+	}
+
+	m.current = registers.ElectricalData{
+		Voltage:       float32(voltage),
+		Current:       float32(current),
+		ActivePower:   float32(active),
+		ReactivePower: float32(reactive),
+		ApparentPower: float32(apparent),
+		PowerFactor:   float32(w.PowerFactor),
+		Frequency:     float32(w.Frequency),
+		ActiveEnergy:  float32(m.energy),
+	}
+}
+
+// exceptionFrame 构造异常应答帧
+func exceptionFrame(slaveID byte, function byte, code byte) []byte {
+	frame := []byte{slaveID, function | 0x80, code}
+	return binary.LittleEndian.AppendUint16(frame, modbus.CalculateCRC16(frame))
+}
+
+// putFloat32 以大端 IEEE754 追加浮点数
+func putFloat32(dst []byte, value float32) []byte {
+	return binary.BigEndian.AppendUint32(dst, math.Float32bits(value))
+}
@@ -0,0 +1,259 @@
+package simulator
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/registers"
+)
+
+// fakeClock 可手动推进的时钟
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestMeter(clock *fakeClock) *Meter {
+	return NewMeter(Config{
+		SlaveID:  0x0C,
+		Waveform: Waveform{Voltage: 220, Frequency: 50, PowerFactor: 1, Current: 10, InitialEnergy: 1},
+		Clock:    clock.Now,
+	})
+}
+
+func TestMeter_ReadElectricalBlock(t *testing.T) {
+	m := newTestMeter(&fakeClock{now: time.Unix(0, 0)})
+
+	resp := m.Handle(modbus.BuildReadFrame(0x0C, registers.RegVoltage, 16))
+	frame, err := modbus.ParseResponse(resp)
+	if err != nil {
+		t.Fatalf("ParseResponse failed: %v", err)
+	}
+	if len(frame.Data) != 32 {
+		t.Fatalf("expected 32 data bytes, got %d", len(frame.Data))
+	}
+	if got := registers.ParseFloat32(frame.Data[0:4]); got != 220 {
+		t.Fatalf("voltage: got %v want 220", got)
+	}
+	if got := registers.ParseFloat32(frame.Data[8:12]); got != 2200 {
+		t.Fatalf("active power: got %v want 2200", got)
+	}
+	if got := registers.ParseFloat32(frame.Data[28:32]); got != 50 {
+		t.Fatalf("frequency: got %v want 50", got)
+	}
+}
+
+func TestMeter_PartialBlockRead(t *testing.T) {
+	m := newTestMeter(&fakeClock{now: time.Unix(0, 0)})
+
+	// 单独读取频率 0x200E
+	frame, err := modbus.ParseResponse(m.Handle(modbus.BuildReadFrame(0x0C, registers.RegFrequency, 2)))
+	if err != nil {
+		t.Fatalf("ParseResponse failed: %v", err)
+	}
+	if got := registers.ParseFloat32(frame.Data); got != 50 {
+		t.Fatalf("frequency: got %v want 50", got)
+	}
+}
+
+func TestMeter_IllegalAddress(t *testing.T) {
+	m := newTestMeter(&fakeClock{now: time.Unix(0, 0)})
+
+	cases := []struct {
+		name  string
+		start uint16
+		qty   uint16
+	}{
+		{"unknown block", 0x1000, 2},
+		{"crosses block end", 0x200E, 4},
+		{"energy overrun", registers.RegActiveEnergy, 4},
+	}
+	for _, tc := range cases {
+		frame, err := modbus.ParseResponse(m.Handle(modbus.BuildReadFrame(0x0C, tc.start, tc.qty)))
+		if err == nil {
+			t.Fatalf("%s: expected exception", tc.name)
+		}
+		if frame == nil || frame.Data[0] != modbus.ExceptionIllegalDataAddr {
+			t.Fatalf("%s: expected illegal data address, got %#v", tc.name, frame)
+		}
+	}
+}
+
+func TestMeter_IgnoresOtherSlaveAndBadCRC(t *testing.T) {
+	m := newTestMeter(&fakeClock{now: time.Unix(0, 0)})
+
+	if resp := m.Handle(modbus.BuildReadFrame(0x01, registers.RegVoltage, 2)); resp != nil {
+		t.Fatalf("expected no response for other slave, got % X", resp)
+	}
+	req := modbus.BuildReadFrame(0x0C, registers.RegVoltage, 2)
+	req[7] ^= 0xFF
+	if resp := m.Handle(req); resp != nil {
+		t.Fatalf("expected no response for bad CRC, got % X", resp)
+	}
+}
+
+func TestMeter_EnergyAccumulationAndLoadStep(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	m := NewMeter(Config{
+		SlaveID: 0x0C,
+		Waveform: Waveform{
+			Voltage: 200, Frequency: 50, PowerFactor: 1, Current: 5,
+			LoadSteps: []LoadStep{{At: time.Hour, Current: 0}},
+		},
+		Clock: clock.Now,
+	})
+
+	// 1kW 持续 1 小时 => 1kWh，之后负载切除不再累计
+	clock.now = clock.now.Add(time.Hour)
+	snap := m.Snapshot()
+	if math.Abs(float64(snap.ActiveEnergy)-1) > 1e-6 {
+		t.Fatalf("energy after 1h: got %v want 1", snap.ActiveEnergy)
+	}
+	if snap.Current != 0 {
+		t.Fatalf("expected load step to 0A, got %v", snap.Current)
+	}
+
+	clock.now = clock.now.Add(time.Hour)
+	if snap := m.Snapshot(); math.Abs(float64(snap.ActiveEnergy)-1) > 1e-6 {
+		t.Fatalf("energy should stay at 1 without load, got %v", snap.ActiveEnergy)
+	}
+}
+
+func TestMeter_VoltageDrift(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	m := NewMeter(Config{
+		SlaveID:  0x0C,
+		Waveform: Waveform{Voltage: 220, VoltageDrift: 10, DriftPeriod: 4 * time.Second, PowerFactor: 1},
+		Clock:    clock.Now,
+	})
+
+	// 四分之一周期处达到漂移峰值
+	clock.now = clock.now.Add(time.Second)
+	if got := m.Snapshot().Voltage; math.Abs(float64(got)-230) > 1e-3 {
+		t.Fatalf("voltage at peak: got %v want 230", got)
+	}
+}
+
+func TestMeter_FaultInjection(t *testing.T) {
+	m := newTestMeter(&fakeClock{now: time.Unix(0, 0)})
+	req := modbus.BuildReadFrame(0x0C, registers.RegVoltage, 2)
+
+	m.InjectFault(FaultTimeout, 1)
+	m.InjectFault(FaultCRC, 1)
+	m.InjectFault(FaultPartialFrame, 1)
+
+	if resp := m.Handle(req); resp != nil {
+		t.Fatalf("timeout fault: expected no response")
+	}
+	if _, err := modbus.ParseResponse(m.Handle(req)); err == nil {
+		t.Fatalf("crc fault: expected parse error")
+	}
+	if resp := m.Handle(req); len(resp) >= 9 {
+		t.Fatalf("partial fault: expected truncated frame, got % X", resp)
+	}
+	if _, err := modbus.ParseResponse(m.Handle(req)); err != nil {
+		t.Fatalf("expected healthy response after queued faults, got %v", err)
+	}
+}
+
+func TestMeter_RandomFaultRates(t *testing.T) {
+	m := NewMeter(Config{SlaveID: 0x0C, Waveform: DefaultWaveform(), Faults: FaultRates{Timeout: 1}})
+	if resp := m.Handle(modbus.BuildReadFrame(0x0C, registers.RegVoltage, 2)); resp != nil {
+		t.Fatalf("expected timeout with rate 1, got % X", resp)
+	}
+}
+
+func TestMemoryTransport_RoundTrip(t *testing.T) {
+	m := newTestMeter(&fakeClock{now: time.Unix(0, 0)})
+	tr := NewMemoryTransport(m, 5*time.Millisecond)
+	if err := tr.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer tr.Close()
+
+	if _, err := tr.Write(modbus.BuildReadFrame(0x0C, registers.RegActiveEnergy, 2)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	buffer := make([]byte, 256)
+	n, err := tr.ReadWithTimeout(buffer, time.Second)
+	if err != nil {
+		t.Fatalf("ReadWithTimeout failed: %v", err)
+	}
+	frame, err := modbus.ParseResponse(buffer[:n])
+	if err != nil {
+		t.Fatalf("ParseResponse failed: %v", err)
+	}
+	if got := math.Float32frombits(binary.BigEndian.Uint32(frame.Data)); got != 1 {
+		t.Fatalf("energy: got %v want 1", got)
+	}
+
+	// 无应答时应在超时后返回错误
+	if _, err := tr.ReadWithTimeout(buffer, 20*time.Millisecond); err == nil {
+		t.Fatalf("expected timeout error")
+	}
+}
+
+func TestMeter_WriteParams(t *testing.T) {
+	m := newTestMeter(&fakeClock{now: time.Unix(0, 0)})
+
+	req := modbus.NewWriteSingleRequest(0x0C, 0x000C, 2)
+	if _, err := req.ParseResponse(m.Handle(req.Encode())); err != nil {
+		t.Fatalf("write single failed: %v", err)
+	}
+	if got := m.Param(0x000C); got != 2 {
+		t.Fatalf("baud param: got %d want 2", got)
+	}
+
+	multi := modbus.NewWriteMultipleRequest(0x0C, 0x0005, []uint16{1, 0x20})
+	if _, err := multi.ParseResponse(m.Handle(multi.Encode())); err != nil {
+		t.Fatalf("write multiple failed: %v", err)
+	}
+	if m.Param(0x0005) != 1 || m.Param(0x0006) != 0x20 {
+		t.Fatalf("params not written: protocol=%d addr=%d", m.Param(0x0005), m.Param(0x0006))
+	}
+
+	// 通讯地址写入后立即生效，按新地址读回参数寄存器
+	if m.SlaveID() != 0x20 {
+		t.Fatalf("slave id should follow addr param: got %02X", m.SlaveID())
+	}
+	if resp := m.Handle(modbus.BuildReadFrame(0x0C, 0x0005, 2)); resp != nil {
+		t.Fatalf("old address should no longer respond")
+	}
+	read := modbus.NewReadRequest(0x20, 0x0005, 2)
+	frame, err := read.ParseResponse(m.Handle(read.Encode()))
+	if err != nil {
+		t.Fatalf("read params failed: %v", err)
+	}
+	if binary.BigEndian.Uint16(frame.Data[2:4]) != 0x20 {
+		t.Fatalf("read back mismatch: % X", frame.Data)
+	}
+
+	// 写入只读的电参量区应返回非法地址异常
+	bad := modbus.NewWriteSingleRequest(0x20, registers.RegVoltage, 1)
+	if _, err := bad.ParseResponse(m.Handle(bad.Encode())); err == nil {
+		t.Fatalf("expected exception writing electrical registers")
+	}
+}
+
+func TestMeter_ClearEnergy(t *testing.T) {
+	m := newTestMeter(&fakeClock{now: time.Unix(0, 0)})
+	if m.Snapshot().ActiveEnergy == 0 {
+		t.Fatalf("expected initial energy")
+	}
+
+	req := modbus.NewWriteMultipleRequest(0x0C, registers.RegClearEnergy, []uint16{registers.ClearEnergyCommand})
+	if _, err := req.ParseResponse(m.Handle(req.Encode())); err != nil {
+		t.Fatalf("clear energy failed: %v", err)
+	}
+	frame, err := modbus.ParseResponse(m.Handle(modbus.BuildReadFrame(0x0C, registers.RegActiveEnergy, 2)))
+	if err != nil {
+		t.Fatalf("read energy failed: %v", err)
+	}
+	if got := registers.ParseFloat32(frame.Data); got != 0 {
+		t.Fatalf("energy after clear: got %v want 0", got)
+	}
+}
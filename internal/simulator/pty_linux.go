@@ -0,0 +1,82 @@
+//go:build linux
+
+package simulator
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// PTY 伪终端对：仿真电表在主端应答，应用通过 SlavePath 像普通串口一样打开从端
+type PTY struct {
+	SlavePath string
+	master    *os.File
+	done      chan struct{}
+}
+
+// ServePTY 创建伪终端并在后台应答主端收到的请求
+// 仿真电表只支持 8 字节的读请求帧，因此按 8 字节切分请求
+func (m *Meter) ServePTY() (*PTY, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("打开 /dev/ptmx 失败: %v", err)
+	}
+
+	unlock := 0
+	if err := ioctl(master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		master.Close()
+		return nil, fmt.Errorf("解锁伪终端失败: %v", err)
+	}
+	var ptn uint32
+	if err := ioctl(master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&ptn))); err != nil {
+		master.Close()
+		return nil, fmt.Errorf("获取伪终端编号失败: %v", err)
+	}
+
+	p := &PTY{
+		SlavePath: fmt.Sprintf("/dev/pts/%d", ptn),
+		master:    master,
+		done:      make(chan struct{}),
+	}
+	go p.serve(m)
+	return p, nil
+}
+
+// serve 读取请求并写回应答，直到主端关闭
+func (p *PTY) serve(m *Meter) {
+	defer close(p.done)
+
+	var pending []byte
+	buffer := make([]byte, 256)
+	for {
+		n, err := p.master.Read(buffer)
+		if err != nil {
+			return
+		}
+		pending = append(pending, buffer[:n]...)
+		for len(pending) >= 8 {
+			request := pending[:8]
+			pending = pending[8:]
+			if response := m.Handle(request); response != nil {
+				p.master.Write(response)
+			}
+		}
+	}
+}
+
+// Close 关闭伪终端
+func (p *PTY) Close() error {
+	err := p.master.Close()
+	<-p.done
+	return err
+}
+
+func ioctl(fd uintptr, request uintptr, arg uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+//go:build linux
+
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"DDSUViewer/internal/modbus"
+	"DDSUViewer/internal/registers"
+	"DDSUViewer/internal/serial"
+	goserial "go.bug.st/serial"
+)
+
+func TestServePTY_SerialRoundTrip(t *testing.T) {
+	m := NewMeter(Config{SlaveID: 0x0C, Waveform: DefaultWaveform()})
+	pty, err := m.ServePTY()
+	if err != nil {
+		t.Skipf("pty unavailable: %v", err)
+	}
+	defer pty.Close()
+
+	conn := serial.NewConnection(serial.Config{
+		Port: pty.SlavePath, BaudRate: 9600, DataBits: 8,
+		StopBits: goserial.OneStopBit, Parity: goserial.NoParity,
+	})
+	if err := conn.Open(); err != nil {
+		t.Skipf("open pty slave failed: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(modbus.BuildReadFrame(0x0C, registers.RegVoltage, 2)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var response []byte
+	buffer := make([]byte, 256)
+	deadline := time.Now().Add(2 * time.Second)
+	for len(response) < 9 && time.Now().Before(deadline) {
+		n, _ := conn.ReadWithTimeout(buffer, 200*time.Millisecond)
+		response = append(response, buffer[:n]...)
+	}
+
+	frame, err := modbus.ParseResponse(response)
+	if err != nil {
+		t.Fatalf("ParseResponse failed: %v (% X)", err, response)
+	}
+	if got := registers.ParseFloat32(frame.Data); got < 200 || got > 240 {
+		t.Fatalf("unexpected voltage %v", got)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package simulator
+
+import (
+	"fmt"
+)
+
+// PTY 伪终端对（仅 Linux 支持）
+type PTY struct {
+	SlavePath string
+}
+
+// ServePTY 当前平台不支持伪终端，请改用 MemoryTransport
+func (m *Meter) ServePTY() (*PTY, error) {
+	return nil, fmt.Errorf("当前平台不支持伪终端")
+}
+
+// Close 关闭伪终端
+func (p *PTY) Close() error {
+	return nil
+}
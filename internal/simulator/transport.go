@@ -0,0 +1,160 @@
+package simulator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"DDSUViewer/internal/transport"
+)
+
+// 编译期检查 MemoryTransport 实现 transport.Transport 与 transport.LossDetector
+var (
+	_ transport.Transport    = (*MemoryTransport)(nil)
+	_ transport.LossDetector = (*MemoryTransport)(nil)
+)
+
+// MemoryTransport 内存链路：直接把请求交给仿真电表，应答进入读缓冲
+// 可连接多台电表模拟一条 RS485 总线，各电表只应答发给自己地址的请求
+type MemoryTransport struct {
+	meters        []*Meter
+	responseDelay time.Duration
+	mutex         sync.Mutex
+	isOpen        bool
+	lost          bool
+	rx            []byte
+	notify        chan struct{}
+}
+
+// NewMemoryTransport 创建连接到仿真电表的内存链路
+// responseDelay 模拟从站处理时间，为 0 时立即应答
+func NewMemoryTransport(meter *Meter, responseDelay time.Duration) *MemoryTransport {
+	return NewBusTransport([]*Meter{meter}, responseDelay)
+}
+
+// NewBusTransport 创建连接多台仿真电表的内存总线
+func NewBusTransport(meters []*Meter, responseDelay time.Duration) *MemoryTransport {
+	return &MemoryTransport{
+		meters:        meters,
+		responseDelay: responseDelay,
+		notify:        make(chan struct{}, 1),
+	}
+}
+
+// Open 打开链路
+func (t *MemoryTransport) Open() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.isOpen {
+		return fmt.Errorf("仿真链路已打开")
+	}
+	t.isOpen = true
+	t.lost = false
+	t.rx = nil
+	return nil
+}
+
+// Close 关闭链路
+func (t *MemoryTransport) Close() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.isOpen = false
+	return nil
+}
+
+// Write 写入请求帧，仿真电表的应答在 responseDelay 后可读
+func (t *MemoryTransport) Write(data []byte) (int, error) {
+	t.mutex.Lock()
+	if !t.isOpen {
+		t.mutex.Unlock()
+		return 0, fmt.Errorf("仿真链路未打开")
+	}
+	t.mutex.Unlock()
+
+	var response []byte
+	for _, m := range t.meters {
+		if response = m.Handle(append([]byte(nil), data...)); response != nil {
+			break
+		}
+	}
+	if response != nil {
+		if t.responseDelay > 0 {
+			time.AfterFunc(t.responseDelay, func() { t.deliver(response) })
+		} else {
+			t.deliver(response)
+		}
+	}
+	return len(data), nil
+}
+
+// deliver 将应答放入读缓冲并唤醒读取方
+func (t *MemoryTransport) deliver(response []byte) {
+	t.mutex.Lock()
+	if t.isOpen {
+		t.rx = append(t.rx, response...)
+	}
+	t.mutex.Unlock()
+
+	select {
+	case t.notify <- struct{}{}:
+	default:
+	}
+}
+
+// ReadWithTimeout 带超时的读取，无数据时阻塞至超时
+func (t *MemoryTransport) ReadWithTimeout(buffer []byte, timeout time.Duration) (int, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		t.mutex.Lock()
+		if !t.isOpen {
+			t.mutex.Unlock()
+			return 0, fmt.Errorf("仿真链路未打开")
+		}
+		if len(t.rx) > 0 {
+			n := copy(buffer, t.rx)
+			t.rx = t.rx[n:]
+			t.mutex.Unlock()
+			return n, nil
+		}
+		t.mutex.Unlock()
+
+		select {
+		case <-t.notify:
+		case <-timer.C:
+			return 0, fmt.Errorf("读取超时")
+		}
+	}
+}
+
+// Disconnect 模拟链路意外断开（如拔出 USB 串口适配器）：链路关闭且 Lost 返回 true，用于验证自动重连
+func (t *MemoryTransport) Disconnect() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.isOpen = false
+	t.lost = true
+}
+
+// Lost 链路是否已被 Disconnect 断开
+func (t *MemoryTransport) Lost() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.lost
+}
+
+// IsOpen 检查链路是否打开
+func (t *MemoryTransport) IsOpen() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.isOpen
+}
+
+// Name 返回链路描述
+func (t *MemoryTransport) Name() string {
+	if len(t.meters) != 1 {
+		return fmt.Sprintf("仿真总线 (%d 台电表)", len(t.meters))
+	}
+	return fmt.Sprintf("仿真电表 0x%02X", t.meters[0].SlaveID())
+}
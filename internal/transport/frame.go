@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"fmt"
+	"time"
+
+	"DDSUViewer/internal/modbus"
+)
+
+// InterCharTolerance 字符间隔超时的附加容差
+// USB 转串口适配器存在数毫秒的批量传输延迟（如 FTDI 默认 16ms），
+// 仅按 t1.5 判断帧结束会把一帧拆成多段
+const InterCharTolerance = 20 * time.Millisecond
+
+// ReadFrame 通过流式解码器读取一帧 RTU 响应
+// request 为本端刚发送的请求，用于剔除半双工回显；最多等待 timeout，
+// 识别到完整帧后立即返回；残帧之后静默超过 t1.5（含容差）则丢弃残帧继续等待
+func ReadFrame(t Transport, request []byte, timeout time.Duration) ([]byte, error) {
+	timing := TimingOf(t)
+	decoder := modbus.NewDecoder()
+	decoder.ExpectEcho(request)
+
+	buffer := make([]byte, 256)
+	deadline := time.Now().Add(timeout)
+	received := false
+	for {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			break
+		}
+		gapWait := timing.InterCharTimeout + InterCharTolerance
+		inFrame := decoder.Buffered() > 0 && wait > gapWait
+		if inFrame {
+			wait = gapWait
+		}
+
+		// 部分串口驱动超时时返回 (0, nil)，统一按超时处理
+		n, err := t.ReadWithTimeout(buffer, wait)
+		if n > 0 {
+			received = true
+			decoder.Feed(buffer[:n])
+			if frame := decoder.Next(); frame != nil {
+				return frame, nil
+			}
+			continue
+		}
+		if err != nil && !inFrame {
+			break
+		}
+		if inFrame {
+			decoder.Reset() // 帧内静默超时，残帧作废
+		}
+	}
+
+	if received {
+		return nil, fmt.Errorf("响应不完整或校验失败")
+	}
+	return nil, fmt.Errorf("无响应")
+}
+
+// ReadRaw 读取一段原始应答，不做帧识别与 CRC 校验（供调试控制台使用）
+// 最多等待 timeout 收到首字节；此后静默超过 t1.5（含容差）即认为应答结束
+// 同时返回最后一个字节到达的时间，便于扣除结尾的静默等待计算往返耗时
+func ReadRaw(t Transport, timeout time.Duration) ([]byte, time.Time, error) {
+	timing := TimingOf(t)
+	gapWait := timing.InterCharTimeout + InterCharTolerance
+
+	var data []byte
+	var last time.Time
+	buffer := make([]byte, 256)
+	deadline := time.Now().Add(timeout)
+	for {
+		wait := time.Until(deadline)
+		if len(data) > 0 && wait > gapWait {
+			wait = gapWait
+		}
+		if wait <= 0 {
+			break
+		}
+		n, err := t.ReadWithTimeout(buffer, wait)
+		if n > 0 {
+			data = append(data, buffer[:n]...)
+			last = time.Now()
+			continue
+		}
+		if err != nil || len(data) > 0 {
+			break
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, time.Now(), fmt.Errorf("无响应")
+	}
+	return data, last, nil
+}
@@ -0,0 +1,81 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"DDSUViewer/internal/modbus"
+)
+
+// scriptedTransport 每次读取返回脚本中的下一段数据，脚本耗尽后按超时处理
+type scriptedTransport struct {
+	chunks [][]byte
+}
+
+func (s *scriptedTransport) Open() error                    { return nil }
+func (s *scriptedTransport) Close() error                   { return nil }
+func (s *scriptedTransport) IsOpen() bool                   { return true }
+func (s *scriptedTransport) Name() string                   { return "scripted" }
+func (s *scriptedTransport) Write(data []byte) (int, error) { return len(data), nil }
+
+func (s *scriptedTransport) ReadWithTimeout(buffer []byte, timeout time.Duration) (int, error) {
+	if len(s.chunks) == 0 {
+		time.Sleep(timeout)
+		return 0, fmt.Errorf("timeout")
+	}
+	n := copy(buffer, s.chunks[0])
+	s.chunks = s.chunks[1:]
+	return n, nil
+}
+
+func TestReadFrame_SkipsNoiseAndEcho(t *testing.T) {
+	request := modbus.BuildReadFrame(0x0C, 0x2000, 2)
+	resp := rtuResponse(0x0C, []byte{0x43, 0x5C, 0x80, 0x00})
+
+	st := &scriptedTransport{chunks: [][]byte{
+		request[:5], request[5:], // 半双工回显
+		{0x00, 0xFE}, // 线路噪声
+		resp[:4], resp[4:],
+	}}
+	frame, err := ReadFrame(st, request, 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("ReadFrame failed: %v", err)
+	}
+	if !bytes.Equal(frame, resp) {
+		t.Fatalf("frame mismatch: got % X want % X", frame, resp)
+	}
+}
+
+func TestReadFrame_Errors(t *testing.T) {
+	request := modbus.BuildReadFrame(0x0C, 0x2000, 2)
+
+	if _, err := ReadFrame(&scriptedTransport{}, request, 50*time.Millisecond); err == nil || err.Error() != "无响应" {
+		t.Fatalf("expected no-response error, got %v", err)
+	}
+
+	resp := rtuResponse(0x0C, []byte{0x43, 0x5C, 0x80, 0x00})
+	partial := &scriptedTransport{chunks: [][]byte{resp[:5]}}
+	if _, err := ReadFrame(partial, request, 100*time.Millisecond); err == nil || err.Error() == "无响应" {
+		t.Fatalf("expected incomplete-frame error, got %v", err)
+	}
+}
+
+func TestReadRaw_KeepsCorruptFrame(t *testing.T) {
+	resp := rtuResponse(0x0C, []byte{0x43, 0x5C, 0x80, 0x00})
+	resp[len(resp)-1] ^= 0xFF // 破坏 CRC，ReadFrame 会丢弃，ReadRaw 须原样返回
+
+	st := &scriptedTransport{chunks: [][]byte{resp[:3], resp[3:]}}
+	data, _, err := ReadRaw(st, 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("ReadRaw failed: %v", err)
+	}
+	if !bytes.Equal(data, resp) {
+		t.Fatalf("data mismatch: got % X want % X", data, resp)
+	}
+
+	if _, _, err := ReadRaw(&scriptedTransport{}, 50*time.Millisecond); err == nil {
+		t.Fatalf("expected no-response error")
+	}
+}
@@ -0,0 +1,219 @@
+package transport
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"DDSUViewer/internal/modbus"
+)
+
+// DefaultDialTimeout TCP 连接超时
+const DefaultDialTimeout = 3 * time.Second
+
+// ModbusTCPClient Modbus TCP 客户端链路
+// 对上层仍表现为 RTU 字节流：Write 接收 RTU 帧并转换为 MBAP 报文，
+// ReadWithTimeout 将收到的 MBAP 响应还原为带 CRC 的 RTU 帧，
+// 因此轮询器与 modbus.ParseResponse 无需区分链路类型；连接断开后在下一次写入时自动重连
+type ModbusTCPClient struct {
+	address       string
+	dialTimeout   time.Duration
+	conn          net.Conn
+	mutex         sync.Mutex
+	isOpen        bool // 用户是否要求打开链路，与底层 socket 是否存活无关
+	transactionID uint16
+	rxBuf         []byte // 未组成完整 ADU 的原始接收数据
+	pending       []byte // 已还原、待上层读取的 RTU 数据
+}
+
+// NewModbusTCPClient 创建 Modbus TCP 客户端
+func NewModbusTCPClient(host string, port int) *ModbusTCPClient {
+	if port == 0 {
+		port = modbus.DefaultTCPPort
+	}
+	return &ModbusTCPClient{
+		address:     net.JoinHostPort(host, strconv.Itoa(port)),
+		dialTimeout: DefaultDialTimeout,
+	}
+}
+
+// Open 建立 TCP 连接
+func (c *ModbusTCPClient) Open() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.isOpen {
+		return fmt.Errorf("连接已打开")
+	}
+
+	if err := c.dial(); err != nil {
+		return err
+	}
+	c.isOpen = true
+	return nil
+}
+
+// dial 建立底层 socket 并清空收发缓冲（调用方持有锁）
+func (c *ModbusTCPClient) dial() error {
+	conn, err := net.DialTimeout("tcp", c.address, c.dialTimeout)
+	if err != nil {
+		return fmt.Errorf("连接 %s 失败: %v", c.address, err)
+	}
+	c.conn = conn
+	c.rxBuf = nil
+	c.pending = nil
+	return nil
+}
+
+// dropConn 关闭失效的 socket，等待下一次写入时重连（调用方持有锁）
+func (c *ModbusTCPClient) dropConn(cause error) {
+	if c.conn == nil {
+		return
+	}
+	log.Printf("%s 连接断开，将自动重连: %v", c.address, cause)
+	c.conn.Close()
+	c.conn = nil
+}
+
+// Close 关闭 TCP 连接，可打断进行中的读取
+func (c *ModbusTCPClient) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.isOpen {
+		return nil
+	}
+
+	c.isOpen = false
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Write 将 RTU 帧封装为 MBAP 报文发送，每次请求分配新的事务ID；连接已断开时先重连
+// 帧末两个字节视为 CRC 并被去除，调用方须传入完整的 RTU 帧
+func (c *ModbusTCPClient) Write(data []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.isOpen {
+		return 0, fmt.Errorf("连接未打开")
+	}
+
+	c.transactionID++
+	adu, err := modbus.BuildTCPFrame(c.transactionID, data)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.conn == nil {
+		if err := c.dial(); err != nil {
+			return 0, err
+		}
+		log.Printf("%s 重连成功", c.address)
+	}
+
+	// 丢弃上一事务残留的数据
+	c.rxBuf = nil
+	c.pending = nil
+
+	c.conn.SetWriteDeadline(time.Now().Add(c.dialTimeout))
+	if _, err := c.conn.Write(adu); err != nil {
+		c.dropConn(err)
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// ReadWithTimeout 带超时读取，返回还原后的 RTU 帧数据
+// 阻塞读取期间不持有锁，Close 可随时打断；超时以外的读取错误视为连接失效
+func (c *ModbusTCPClient) ReadWithTimeout(buffer []byte, timeout time.Duration) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	chunk := make([]byte, 260)
+	for len(c.pending) == 0 {
+		if !c.isOpen {
+			return 0, fmt.Errorf("连接未打开")
+		}
+		if c.conn == nil {
+			return 0, fmt.Errorf("连接已断开")
+		}
+		if err := c.extractADU(); err != nil {
+			return 0, err
+		}
+		if len(c.pending) > 0 {
+			break
+		}
+
+		conn := c.conn
+		conn.SetReadDeadline(deadline)
+		c.mutex.Unlock()
+		n, err := conn.Read(chunk)
+		c.mutex.Lock()
+
+		if c.conn != conn {
+			// 读取期间链路被关闭或重建，本次数据作废
+			return 0, fmt.Errorf("连接已关闭")
+		}
+		c.rxBuf = append(c.rxBuf, chunk[:n]...)
+		if err != nil {
+			if !isTimeout(err) {
+				c.dropConn(err)
+			}
+			return 0, err
+		}
+	}
+
+	n := copy(buffer, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+// extractADU 从接收缓冲中取出一个完整且事务ID匹配的 ADU
+func (c *ModbusTCPClient) extractADU() error {
+	for len(c.rxBuf) >= modbus.MBAPHeaderLength {
+		header, err := modbus.ParseMBAPHeader(c.rxBuf)
+		if err != nil {
+			// 报文头错乱，无法重新定位边界，丢弃全部缓冲
+			c.rxBuf = nil
+			return fmt.Errorf("MBAP报文错误: %v", err)
+		}
+
+		total := 6 + int(header.Length)
+		if len(c.rxBuf) < total {
+			return nil // 等待更多数据
+		}
+
+		pdu := c.rxBuf[modbus.MBAPHeaderLength:total]
+		c.rxBuf = c.rxBuf[total:]
+
+		// 忽略迟到的旧事务响应
+		if header.TransactionID != c.transactionID {
+			continue
+		}
+
+		c.pending = modbus.TCPToRTU(header.UnitID, pdu)
+		return nil
+	}
+	return nil
+}
+
+// IsOpen 检查连接是否打开
+func (c *ModbusTCPClient) IsOpen() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.isOpen
+}
+
+// Name 返回链路描述
+func (c *ModbusTCPClient) Name() string {
+	return fmt.Sprintf("Modbus TCP %s", c.address)
+}
@@ -0,0 +1,202 @@
+package transport
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"DDSUViewer/internal/modbus"
+)
+
+// startMBAPServer 启动本地 Modbus TCP 应答端：先回一个旧事务ID的报文，再回正确响应
+func startMBAPServer(t *testing.T) (string, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		req := make([]byte, 12)
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		txID := binary.BigEndian.Uint16(req[0:2])
+		unit := req[6]
+
+		pdu := []byte{modbus.FunctionReadHoldingRegisters, 0x04, 0x43, 0x5C, 0x80, 0x00}
+		reply := func(id uint16) []byte {
+			adu := []byte{byte(id >> 8), byte(id), 0x00, 0x00, 0x00, byte(len(pdu) + 1), unit}
+			return append(adu, pdu...)
+		}
+		conn.Write(reply(txID - 1))
+		// 分两段发送，验证客户端能拼接不完整的 ADU
+		resp := reply(txID)
+		conn.Write(resp[:5])
+		time.Sleep(10 * time.Millisecond)
+		conn.Write(resp[5:])
+		time.Sleep(100 * time.Millisecond)
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestModbusTCPClient_ReadHoldingRegisters(t *testing.T) {
+	host, port := startMBAPServer(t)
+
+	c := NewModbusTCPClient(host, port)
+	if err := c.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer c.Close()
+
+	if _, err := c.Write(modbus.BuildReadFrame(0x0C, 0x2000, 2)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	buffer := make([]byte, 256)
+	n, err := c.ReadWithTimeout(buffer, time.Second)
+	if err != nil {
+		t.Fatalf("ReadWithTimeout failed: %v", err)
+	}
+
+	frame, err := modbus.ParseResponse(buffer[:n])
+	if err != nil {
+		t.Fatalf("ParseResponse failed: %v", err)
+	}
+	if frame.SlaveID != 0x0C {
+		t.Fatalf("unit id mismatch: got %02X want 0C", frame.SlaveID)
+	}
+	if got := binary.BigEndian.Uint32(frame.Data); got != 0x435C8000 {
+		t.Fatalf("data mismatch: got %08X", got)
+	}
+}
+
+func TestModbusTCPClient_NotOpen(t *testing.T) {
+	c := NewModbusTCPClient("127.0.0.1", 0)
+	if c.IsOpen() {
+		t.Fatalf("expected closed client")
+	}
+	if _, err := c.Write([]byte{0x01, 0x03, 0x00, 0x00}); err == nil {
+		t.Fatalf("expected error writing to closed client")
+	}
+	if c.Name() != "Modbus TCP 127.0.0.1:502" {
+		t.Fatalf("unexpected name: %s", c.Name())
+	}
+}
+
+// startDroppingMBAPServer 启动本地 Modbus TCP 网关模拟：每个连接只应答一次请求随后断开
+func startDroppingMBAPServer(t *testing.T) (string, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		ln.Close()
+		<-done
+	})
+
+	go func() {
+		defer close(done)
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			req := make([]byte, 12)
+			if _, err := io.ReadFull(conn, req); err == nil {
+				pdu := []byte{modbus.FunctionReadHoldingRegisters, 0x04, 0x43, 0x5C, 0x80, 0x00}
+				adu := []byte{req[0], req[1], 0x00, 0x00, 0x00, byte(len(pdu) + 1), req[6]}
+				conn.Write(append(adu, pdu...))
+			}
+			conn.Close()
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestModbusTCPClient_ReconnectAfterServerClose(t *testing.T) {
+	host, port := startDroppingMBAPServer(t)
+
+	c := NewModbusTCPClient(host, port)
+	if err := c.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer c.Close()
+
+	buffer := make([]byte, 256)
+	for round := 0; round < 3; round++ {
+		if _, err := c.Write(modbus.BuildReadFrame(0x0C, 0x2000, 2)); err != nil {
+			t.Fatalf("round %d: Write failed: %v", round, err)
+		}
+		n, err := c.ReadWithTimeout(buffer, time.Second)
+		if err != nil {
+			t.Fatalf("round %d: ReadWithTimeout failed: %v", round, err)
+		}
+		if _, err := modbus.ParseResponse(buffer[:n]); err != nil {
+			t.Fatalf("round %d: ParseResponse failed: %v", round, err)
+		}
+
+		// 网关已关闭连接，下一次读取应检测到断开，随后的写入自动重连
+		if _, err := c.ReadWithTimeout(buffer, time.Second); err == nil {
+			t.Fatalf("round %d: expected error after server closed connection", round)
+		}
+		if !c.IsOpen() {
+			t.Fatalf("round %d: link should stay open for reconnect", round)
+		}
+	}
+}
+
+func TestModbusTCPClient_CloseInterruptsRead(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer ln.Close()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	c := NewModbusTCPClient(addr.IP.String(), addr.Port)
+	if err := c.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	server := <-accepted
+	defer server.Close()
+
+	// 网关不应答，阻塞中的读取应被 Close 打断而不是等到超时
+	result := make(chan error, 1)
+	go func() {
+		_, err := c.ReadWithTimeout(make([]byte, 256), 5*time.Second)
+		result <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	c.Close()
+	select {
+	case err := <-result:
+		if err == nil {
+			t.Fatalf("expected error from interrupted read")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Close did not interrupt the pending read")
+	}
+}
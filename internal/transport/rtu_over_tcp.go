@@ -0,0 +1,151 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"DDSUViewer/internal/modbus"
+)
+
+// RTUOverTCPConnection RTU over TCP 链路
+// 适用于工作在 "TCP Server" 模式的串口服务器（USR、Moxa 等），
+// 原样透传带 CRC 的 RTU 帧；连接断开后在下一次写入时自动重连
+type RTUOverTCPConnection struct {
+	address     string
+	dialTimeout time.Duration
+	conn        net.Conn
+	mutex       sync.Mutex
+	isOpen      bool // 用户是否要求打开链路，与底层 socket 是否存活无关
+}
+
+// NewRTUOverTCPConnection 创建 RTU over TCP 链路
+func NewRTUOverTCPConnection(host string, port int) *RTUOverTCPConnection {
+	if port == 0 {
+		port = modbus.DefaultTCPPort
+	}
+	return &RTUOverTCPConnection{
+		address:     net.JoinHostPort(host, strconv.Itoa(port)),
+		dialTimeout: DefaultDialTimeout,
+	}
+}
+
+// Open 建立 TCP 连接
+func (c *RTUOverTCPConnection) Open() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.isOpen {
+		return fmt.Errorf("连接已打开")
+	}
+
+	if err := c.dial(); err != nil {
+		return err
+	}
+	c.isOpen = true
+	return nil
+}
+
+// dial 建立底层 socket（调用方持有锁）
+func (c *RTUOverTCPConnection) dial() error {
+	conn, err := net.DialTimeout("tcp", c.address, c.dialTimeout)
+	if err != nil {
+		return fmt.Errorf("连接 %s 失败: %v", c.address, err)
+	}
+	c.conn = conn
+	return nil
+}
+
+// dropConn 关闭失效的 socket，等待下一次写入时重连（调用方持有锁）
+func (c *RTUOverTCPConnection) dropConn(cause error) {
+	if c.conn == nil {
+		return
+	}
+	log.Printf("%s 连接断开，将自动重连: %v", c.address, cause)
+	c.conn.Close()
+	c.conn = nil
+}
+
+// Close 关闭 TCP 连接
+func (c *RTUOverTCPConnection) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.isOpen {
+		return nil
+	}
+
+	c.isOpen = false
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Write 写入 RTU 帧，连接已断开时先重连
+func (c *RTUOverTCPConnection) Write(data []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.isOpen {
+		return 0, fmt.Errorf("连接未打开")
+	}
+
+	if c.conn == nil {
+		if err := c.dial(); err != nil {
+			return 0, err
+		}
+		log.Printf("%s 重连成功", c.address)
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(c.dialTimeout))
+	n, err := c.conn.Write(data)
+	if err != nil {
+		c.dropConn(err)
+	}
+	return n, err
+}
+
+// ReadWithTimeout 带超时的读取
+func (c *RTUOverTCPConnection) ReadWithTimeout(buffer []byte, timeout time.Duration) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.isOpen {
+		return 0, fmt.Errorf("连接未打开")
+	}
+	if c.conn == nil {
+		return 0, fmt.Errorf("连接已断开")
+	}
+
+	c.conn.SetReadDeadline(time.Now().Add(timeout))
+	n, err := c.conn.Read(buffer)
+	if err != nil && !isTimeout(err) {
+		c.dropConn(err)
+	}
+	return n, err
+}
+
+// IsOpen 检查链路是否打开
+func (c *RTUOverTCPConnection) IsOpen() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.isOpen
+}
+
+// Name 返回链路描述
+func (c *RTUOverTCPConnection) Name() string {
+	return fmt.Sprintf("RTU over TCP %s", c.address)
+}
+
+// isTimeout 判断是否为读写超时（超时不代表连接失效）
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,105 @@
+package transport
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"DDSUViewer/internal/modbus"
+)
+
+// rtuResponse 构造 0x03 响应帧（含 CRC）
+func rtuResponse(slaveID byte, data []byte) []byte {
+	resp := []byte{slaveID, modbus.FunctionReadHoldingRegisters, byte(len(data))}
+	resp = append(resp, data...)
+	crc := modbus.CalculateCRC16(resp)
+	return append(resp, byte(crc), byte(crc>>8))
+}
+
+// startRTUServer 启动本地串口服务器模拟：每个连接只应答一次请求随后断开
+func startRTUServer(t *testing.T, requests chan<- []byte) (string, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			buf := make([]byte, 256)
+			n, err := conn.Read(buf)
+			if err == nil {
+				requests <- append([]byte(nil), buf[:n]...)
+				conn.Write(rtuResponse(buf[0], []byte{0x43, 0x5C, 0x80, 0x00}))
+			}
+			conn.Close()
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestRTUOverTCP_PassThroughAndReconnect(t *testing.T) {
+	requests := make(chan []byte, 4)
+	host, port := startRTUServer(t, requests)
+
+	c := NewRTUOverTCPConnection(host, port)
+	if err := c.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer c.Close()
+
+	req := modbus.BuildReadFrame(0x0C, 0x2000, 2)
+	buffer := make([]byte, 256)
+	for round := 0; round < 2; round++ {
+		if _, err := c.Write(req); err != nil {
+			t.Fatalf("round %d: Write failed: %v", round, err)
+		}
+		n, err := c.ReadWithTimeout(buffer, time.Second)
+		if err != nil {
+			t.Fatalf("round %d: ReadWithTimeout failed: %v", round, err)
+		}
+		if _, err := modbus.ParseResponse(buffer[:n]); err != nil {
+			t.Fatalf("round %d: ParseResponse failed: %v", round, err)
+		}
+		if got := <-requests; !bytes.Equal(got, req) {
+			t.Fatalf("round %d: request not passed through: % X", round, got)
+		}
+
+		// 服务器已关闭连接，下一次读取应检测到断开
+		if _, err := c.ReadWithTimeout(buffer, time.Second); err == nil {
+			t.Fatalf("round %d: expected error after server closed connection", round)
+		}
+	}
+
+	if !c.IsOpen() {
+		t.Fatalf("expected link to stay open across reconnects")
+	}
+}
+
+func TestRTUOverTCP_ConnectFailure(t *testing.T) {
+	// 先占用再释放端口，保证无人监听
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	c := NewRTUOverTCPConnection("127.0.0.1", port)
+	c.dialTimeout = 200 * time.Millisecond
+	if err := c.Open(); err == nil {
+		c.Close()
+		t.Fatalf("expected connect error")
+	}
+	if c.IsOpen() {
+		t.Fatalf("expected link to stay closed")
+	}
+}
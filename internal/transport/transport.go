@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"time"
+
+	"DDSUViewer/internal/modbus"
+)
+
+// Transport 通信链路抽象
+// 串口、TCP 网关、仿真器等均实现该接口，轮询器与协议探测器只依赖此接口
+type Transport interface {
+	// Open 打开链路
+	Open() error
+	// Close 关闭链路
+	Close() error
+	// Write 写入数据
+	Write(data []byte) (int, error)
+	// ReadWithTimeout 带超时的读取
+	ReadWithTimeout(buffer []byte, timeout time.Duration) (int, error)
+	// IsOpen 检查链路是否打开
+	IsOpen() bool
+	// Name 链路描述，用于日志与状态展示（如 "串口 COM3"）
+	Name() string
+}
+
+// FrameTimer 可选接口：由物理串口参数决定 RTU 帧时序的链路实现该接口，
+// 未实现的链路（TCP、仿真器）使用 modbus.DefaultRTUTiming
+type FrameTimer interface {
+	FrameTiming() modbus.RTUTiming
+}
+
+// TimingOf 返回链路的 RTU 帧时序
+func TimingOf(t Transport) modbus.RTUTiming {
+	if ft, ok := t.(FrameTimer); ok {
+		return ft.FrameTiming()
+	}
+	return modbus.DefaultRTUTiming()
+}
+
+// LossDetector 可选接口：能识别链路意外断开（如 USB 串口适配器被拔出）的链路实现该接口
+// 检测到断开后链路自行关闭，Lost 返回 true 直到重新打开；上层据此重建链路，而不是对已失效的句柄反复重试
+type LossDetector interface {
+	Lost() bool
+}
+
+// Lost 链路是否已意外断开，未实现 LossDetector 的链路始终返回 false
+func Lost(t Transport) bool {
+	if ld, ok := t.(LossDetector); ok {
+		return ld.Lost()
+	}
+	return false
+}